@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// jsonLines writes one JSON value per line. Events arrive on the Bubbletea
+// goroutine and command errors on the stdin reader, so writes are locked.
+type jsonLines struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+func newJSONLines(w io.Writer) *jsonLines {
+	return &jsonLines{enc: json.NewEncoder(w)}
+}
+
+func (j *jsonLines) write(v any) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.enc.Encode(v)
+}
+
+// Event writes e as a line, e.g.
+// {"event":"zero","interval":2,"round":1,"snapshot":{...}}.
+func (j *jsonLines) Event(e model.Event) {
+	j.write(e)
+}
+
+// commandError is written when a command read from stdin is invalid.
+type commandError struct {
+	Event   string `json:"event"`
+	Command string `json:"command"`
+	Error   string `json:"error"`
+}
+
+// readCommands dispatches each valid line of r as a command, reporting
+// invalid ones to out. It returns at EOF; the timer keeps running until it
+// receives quit, or finishes with --exit-on-done.
+func readCommands(r io.Reader, defaultMode types.Mode, macros map[string]string, dispatch func(parser.Chain), out *jsonLines) {
+	lines := bufio.NewScanner(r)
+	for lines.Scan() {
+		command := strings.TrimSpace(lines.Text())
+		if command == "" {
+			continue
+		}
+		chain, err := parser.ParseChain(command, defaultMode, macros)
+		if err != nil {
+			out.write(commandError{Event: "error", Command: command, Error: err.Error()})
+			continue
+		}
+		dispatch(chain)
+	}
+}
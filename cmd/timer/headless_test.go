@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+func TestReadCommands(t *testing.T) {
+	var out bytes.Buffer
+	var got []string
+	input := "set auto 1 x1\n\n  fly  \nstart; pause\nwarmup\n"
+	macros := map[string]string{"warmup": "set 5:00; start"}
+	readCommands(strings.NewReader(input), types.ModeAuto, macros, func(c parser.Chain) {
+		got = append(got, c.String())
+	}, newJSONLines(&out))
+
+	want := []string{"set auto 1 x1", "start; pause", "set 5:00; start"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("expected %q dispatched, got %q", want, got)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], `{"event":"error","command":"fly","error":"unknown command`) {
+		t.Errorf("expected one error line for fly, got %q", out.String())
+	}
+}
+
+func TestJSONLinesEvent(t *testing.T) {
+	var out bytes.Buffer
+	j := newJSONLines(&out)
+	j.Event(model.Event{Kind: model.EventZero, Interval: 2, Round: 1, Snapshot: model.Snapshot{State: "running", Time: "0:00"}})
+	j.Event(model.Event{Kind: model.EventDone, Interval: 2, Round: 1})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per event, got %q", out.String())
+	}
+	if want := `{"event":"zero","interval":2,"round":1,"snapshot":{"state":"running","time":"0:00",`; !strings.HasPrefix(lines[0], want) {
+		t.Errorf("expected %s..., got %s", want, lines[0])
+	}
+	if want := `{"event":"done",`; !strings.HasPrefix(lines[1], want) {
+		t.Errorf("expected %s..., got %s", want, lines[1])
+	}
+}
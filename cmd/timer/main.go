@@ -1,17 +1,162 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/fifo"
+	"github.com/BobbyGerace/workout-timer/internal/hooks"
 	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/nvim"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/renderer"
+	"github.com/BobbyGerace/workout-timer/internal/server"
 )
 
 func main() {
-	p := tea.NewProgram(model.New(), tea.WithAltScreen())
+	headless := flag.Bool("headless", false, "run without the TUI: read commands from stdin and write events as JSON lines")
+	exitOnDone := flag.Bool("exit-on-done", false, "exit once the workout finishes")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: config: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch flag.Arg(0) {
+	case "status":
+		os.Exit(runStatus(cfg, flag.Args()[1:]))
+	case "watch":
+		os.Exit(runWatch(cfg, flag.Args()[1:]))
+	}
+
+	player, err := audio.New(cfg.Audio, cfg.AudioCommand)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: audio: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Commands are checked against the registered fonts, so user fonts are
+	// registered before the model or any listener parses one.
+	fontErr := renderer.LoadFonts(filepath.Join(config.Dir(), "fonts"))
+
+	m := model.New(cfg, player)
+
+	// Listeners may receive commands before the program exists, so they are
+	// queued here and forwarded once it does.
+	commands := make(chan parser.Chain, 16)
+	send := func(command parser.Chain) {
+		commands <- command
+	}
+
+	sock := server.NewSocket(cfg.SocketPath, cfg.DefaultMode, cfg.Macros, send)
+	m = m.Observe(sock.Publish)
+
+	pipe := fifo.New(cfg.FIFOPath, cfg.DefaultMode, cfg.Macros, send)
+
+	var srv *server.Server
+	if cfg.HTTPPort > 0 {
+		srv = server.New(cfg.HTTPPort, cfg.DefaultMode, cfg.Macros, send)
+		m = m.Observe(srv.Publish)
+	}
+
+	if address := neovimAddress(cfg); address != "" {
+		client, err := nvim.Dial(address, cfg.DefaultMode, cfg.Macros, send)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: neovim: %v\n", err)
+			os.Exit(1)
+		}
+		defer client.Close()
+		m = m.Observe(client.Publish)
+	}
+
+	if runner := hooks.New(cfg.Hooks); runner.Enabled() {
+		m = m.OnEvent(runner.Handle)
+	}
+
+	// warn reports fonts or a listener that failed to load; the timer runs
+	// without them. Headless runs have no screen to show it on.
+	warn := func(msg string) {
+		if *headless {
+			fmt.Fprintf(os.Stderr, "warning: %s\n", msg)
+		}
+		m = m.Warn(msg)
+	}
+
+	if fontErr != nil {
+		warn(strings.ReplaceAll(fontErr.Error(), "\n", "; "))
+	}
+
+	// Commands the socket and FIFO receive wait in the queue until the
+	// program exists.
+	if err := sock.Start(); err != nil {
+		warn(fmt.Sprintf("socket: %v", err))
+	}
+	defer sock.Close()
+
+	if err := pipe.Start(); err != nil {
+		warn(fmt.Sprintf("fifo: %v", err))
+	}
+	defer pipe.Close()
+
+	options := []tea.ProgramOption{tea.WithAltScreen()}
+	var events *jsonLines
+	if *headless {
+		events = newJSONLines(os.Stdout)
+		m = m.OnEvent(events.Event)
+		options = []tea.ProgramOption{tea.WithoutRenderer(), tea.WithInput(nil)}
+	}
+
+	var p *tea.Program
+	if *exitOnDone {
+		m = m.OnEvent(func(e model.Event) {
+			if e.Kind == model.EventDone {
+				go p.Quit() // Quit waits on the program, which is busy calling us
+			}
+		})
+	}
+
+	p = tea.NewProgram(m, options...)
+	go func() {
+		for command := range commands {
+			p.Send(model.CommandMsg(command))
+		}
+	}()
+
+	if *headless {
+		go readCommands(os.Stdin, cfg.DefaultMode, cfg.Macros, send, events)
+	}
+
+	if srv != nil {
+		if err := srv.Start(); err != nil {
+			fmt.Fprintf(os.Stderr, "error: http: %v\n", err)
+			os.Exit(1)
+		}
+		defer srv.Close()
+	}
+
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
 }
+
+// neovimAddress returns the RPC address to connect to, or "" when the
+// Neovim integration is off or there is no Neovim to talk to.
+func neovimAddress(cfg config.Config) string {
+	if !cfg.Neovim {
+		return ""
+	}
+	if cfg.NeovimAddress != "" {
+		return cfg.NeovimAddress
+	}
+	return os.Getenv("NVIM")
+}
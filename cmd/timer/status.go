@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/server"
+)
+
+// runStatus prints one formatted line describing the running instance,
+// e.g. for tmux's status-right: #(timer status --color tmux).
+// It prints nothing and exits 1 when no instance is running.
+func runStatus(cfg config.Config, args []string) int {
+	flags := flag.NewFlagSet("status", flag.ExitOnError)
+	format := flags.String("format", model.DefaultStatusFormat, "template with #{...} placeholders")
+	colorFlag := flags.String("color", "none", "colour codes: none, ansi or tmux")
+	flags.Parse(args)
+
+	color, err := model.ParseStatusColor(*colorFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	snap, err := server.Status(cfg.SocketPath)
+	if err != nil {
+		return 1
+	}
+	fmt.Println(model.FormatStatusLine(*format, snap, color, model.ThemeFor(cfg)))
+	return 0
+}
+
+// runWatch follows the running instance. With --oneline it prints one
+// formatted line per second; otherwise it prints each snapshot as JSON.
+func runWatch(cfg config.Config, args []string) int {
+	flags := flag.NewFlagSet("watch", flag.ExitOnError)
+	oneline := flags.Bool("oneline", false, "print one formatted line per second")
+	format := flags.String("format", model.DefaultStatusFormat, "template with #{...} placeholders (with --oneline)")
+	colorFlag := flags.String("color", "ansi", "colour codes with --oneline: none, ansi or tmux")
+	flags.Parse(args)
+
+	color, err := model.ParseStatusColor(*colorFlag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 2
+	}
+
+	if !*oneline {
+		enc := json.NewEncoder(os.Stdout)
+		err = server.Watch(cfg.SocketPath, func(snap model.Snapshot) {
+			enc.Encode(snap)
+		})
+	} else {
+		err = watchOneline(cfg.SocketPath, *format, color, model.ThemeFor(cfg))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// watchOneline prints the latest snapshot once a second, independent of
+// how often the instance publishes.
+func watchOneline(path, format string, color model.StatusColor, theme model.Theme) error {
+	var mu sync.Mutex
+	var latest model.Snapshot
+	var received bool // nothing is printed before the first snapshot
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Watch(path, func(snap model.Snapshot) {
+			mu.Lock()
+			latest, received = snap, true
+			mu.Unlock()
+		})
+	}()
+
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-ticker.C:
+			mu.Lock()
+			snap, ok := latest, received
+			mu.Unlock()
+			if !ok {
+				continue
+			}
+			fmt.Println(model.FormatStatusLine(format, snap, color, theme))
+		}
+	}
+}
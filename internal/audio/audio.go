@@ -1,60 +1,65 @@
 package audio
 
 import (
-	"bytes"
-	_ "embed"
 	"fmt"
 	"math"
-	"os"
-	"sync"
-	"time"
-
-	oto "github.com/ebitengine/oto/v3"
 )
 
-var (
-	otoCtx  *oto.Context
-	otoOnce sync.Once
-)
+// SampleRate is the rate of every Sound's PCM, matching the oto context.
+const SampleRate = 44100
 
-func initOto() {
-	ctx, readyChan, err := oto.NewContext(&oto.NewContextOptions{
-		SampleRate:   44100,
-		ChannelCount: 1,
-		Format:       oto.FormatSignedInt16LE,
-	})
-	if err != nil {
-		return
-	}
-	<-readyChan
-	otoCtx = ctx
+// Sound is something to play: 16-bit signed little-endian mono PCM at
+// SampleRate, plus a name for backends (and tests) that can't use samples.
+type Sound struct {
+	Name string
+	PCM  []byte
 }
 
-// Beep plays a short alert sound. The command runs in a goroutine so it
-// never blocks the tick loop.
-func Beep() {
-	go func() {
-		if err := playBeep(); err != nil {
-			fmt.Fprint(os.Stderr, "\a")
-		}
-	}()
+// Beep is the short alert played when an interval reaches zero.
+var Beep = Sound{Name: "beep", PCM: generateSine(880, 0.5, SampleRate)}
+
+// Player is an audio backend. Play must return immediately, so it never
+// blocks the tick loop; playback happens in the background.
+type Player interface {
+	Play(s Sound)
 }
 
-func playBeep() error {
-	otoOnce.Do(initOto)
-	if otoCtx == nil {
-		return fmt.Errorf("oto context unavailable")
-	}
-	pcm := generateSine(880, 0.5, 44100)
-	player := otoCtx.NewPlayer(bytes.NewReader(pcm))
-	player.Play()
-	for player.IsPlaying() {
-		time.Sleep(time.Millisecond)
+// New returns the player for a configured backend name:
+//
+//	auto     oto, falling back to the terminal bell (the default)
+//	oto      oto only
+//	bell     the terminal bell
+//	command  an external player fed raw PCM on stdin; command is run with
+//	         `sh -c`, or paplay/aplay is found on $PATH when it is empty
+//	none     no sound
+func New(backend, command string) (Player, error) {
+	switch backend {
+	case "", "auto":
+		return &Oto{Fallback: Bell{}}, nil
+	case "oto":
+		return &Oto{}, nil
+	case "bell":
+		return Bell{}, nil
+	case "command":
+		if command != "" {
+			return Shell(command), nil
+		}
+		c, err := DetectCommand()
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case "none":
+		return Silent{}, nil
 	}
-	player.Close()
-	return nil
+	return nil, fmt.Errorf("unknown audio backend %q: expected auto, oto, bell, command or none", backend)
 }
 
+// Silent discards every sound.
+type Silent struct{}
+
+func (Silent) Play(Sound) {}
+
 var rampDuration = 0.03
 
 // generateSine returns raw 16-bit signed little-endian PCM for a sine wave
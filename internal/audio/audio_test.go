@@ -0,0 +1,335 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewBackends(t *testing.T) {
+	tests := []struct {
+		backend string
+		wantErr bool
+	}{
+		{"", false},
+		{"auto", false},
+		{"oto", false},
+		{"bell", false},
+		{"none", false},
+		{"kazoo", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			p, err := New(tt.backend, "")
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %T", p)
+				}
+				return
+			}
+			if err != nil || p == nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	var r Recorder
+	r.Play(Beep)
+	r.Play(Sound{Name: "gong"})
+
+	got := r.Played()
+	if len(got) != 2 || got[0] != "beep" || got[1] != "gong" {
+		t.Errorf("unexpected recording %v", got)
+	}
+	if !bytes.Equal(r.Sounds()[0].PCM, Beep.PCM) {
+		t.Error("recorder should keep the PCM it was given")
+	}
+}
+
+func TestBell(t *testing.T) {
+	var out bytes.Buffer
+	Bell{Out: &out}.Play(Beep)
+	if out.String() != "\a" {
+		t.Errorf("expected bell character, got %q", out.String())
+	}
+}
+
+func TestCommandPipesPCM(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "pcm")
+	p, err := New("command", "cat > "+out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	p.Play(Sound{Name: "test", PCM: []byte{1, 2, 3, 4}})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, _ := os.ReadFile(out); len(data) == 4 {
+			if !bytes.Equal(data, []byte{1, 2, 3, 4}) {
+				t.Errorf("unexpected PCM %v", data)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("command never received the PCM")
+}
+
+func TestGenerateSineLength(t *testing.T) {
+	pcm := generateSine(440, 0.25, SampleRate)
+	if len(pcm) != SampleRate/4*2 {
+		t.Errorf("expected %d bytes, got %d", SampleRate/4*2, len(pcm))
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+	tests := []struct {
+		input     string
+		wantErr   bool
+		wantTones []Tone
+	}{
+		{"880:500", false, []Tone{{880, 500 * time.Millisecond}}},
+		{"880:150 0:80 880:150", false, []Tone{
+			{880, 150 * time.Millisecond}, {0, 80 * time.Millisecond}, {880, 150 * time.Millisecond},
+		}},
+		{"", true, nil},
+		{"880", true, nil},
+		{"abc:100", true, nil},
+		{"880:0", true, nil},
+		{"-5:100", true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePattern(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(got) != len(tt.wantTones) {
+				t.Fatalf("got %d tones, want %d", len(got), len(tt.wantTones))
+			}
+			for i := range got {
+				if got[i] != tt.wantTones[i] {
+					t.Errorf("[%d] got %+v, want %+v", i, got[i], tt.wantTones[i])
+				}
+			}
+		})
+	}
+}
+
+func TestSynthesizeLength(t *testing.T) {
+	tones, _ := ParsePattern("880:100 0:100 440:200")
+	s := Synthesize("test", tones)
+	want := (SampleRate/10 + SampleRate/10 + SampleRate/5) * 2
+	if s.Name != "test" || len(s.PCM) != want {
+		t.Errorf("expected %d bytes named test, got %d named %q", want, len(s.PCM), s.Name)
+	}
+}
+
+// writeWAV writes a 16-bit PCM WAV file of interleaved samples.
+func writeWAV(t *testing.T, path string, rate, channels int, samples []int16) {
+	t.Helper()
+	var b bytes.Buffer
+	le := binary.LittleEndian
+	b.WriteString("RIFF")
+	binary.Write(&b, le, uint32(36+len(samples)*2))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, le, uint32(16))
+	binary.Write(&b, le, uint16(1))
+	binary.Write(&b, le, uint16(channels))
+	binary.Write(&b, le, uint32(rate))
+	binary.Write(&b, le, uint32(rate*channels*2))
+	binary.Write(&b, le, uint16(channels*2))
+	binary.Write(&b, le, uint16(16))
+	b.WriteString("data")
+	binary.Write(&b, le, uint32(len(samples)*2))
+	binary.Write(&b, le, samples)
+	if err := os.WriteFile(path, b.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadWAV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gong.wav")
+	// 100 stereo frames at half the output rate; left and right average to 1000.
+	samples := make([]int16, 200)
+	for i := 0; i < len(samples); i += 2 {
+		samples[i], samples[i+1] = 500, 1500
+	}
+	writeWAV(t, path, SampleRate/2, 2, samples)
+
+	s, err := LoadFile("zero", path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Name != "zero" || len(s.PCM) != 200*2 {
+		t.Fatalf("expected 200 mono samples named zero, got %d bytes named %q", len(s.PCM), s.Name)
+	}
+	if v := int16(binary.LittleEndian.Uint16(s.PCM[20:])); v < 990 || v > 1010 {
+		t.Errorf("expected downmixed sample near 1000, got %d", v)
+	}
+
+	// The decoded PCM is cached, so the file isn't needed again.
+	os.Remove(path)
+	if again, err := LoadFile("done", path); err != nil || !bytes.Equal(again.PCM, s.PCM) || again.Name != "done" {
+		t.Errorf("expected cached PCM under the new name, got %q, %v", again.Name, err)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	garbage := []string{"bad.wav", "bad.ogg"}
+	for _, name := range garbage {
+		os.WriteFile(filepath.Join(dir, name), []byte("not audio at all"), 0o644)
+	}
+	for _, name := range append(garbage, "missing.wav") {
+		if _, err := LoadFile("zero", filepath.Join(dir, name)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestSpeakerPlaysCommandOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeWAV(t, filepath.Join(dir, "speech.wav"), SampleRate, 1, make([]int16, 100))
+	// The fake TTS records the text it was given and prints a canned WAV.
+	command := "cat > " + filepath.Join(dir, "text") + "; cat " + filepath.Join(dir, "speech.wav")
+
+	var rec Recorder
+	NewSpeaker(command, &rec).Say("Round 3 of 5")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(rec.Sounds()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sounds := rec.Sounds()
+	if len(sounds) != 1 || sounds[0].Name != "speech" || len(sounds[0].PCM) != 200 {
+		t.Fatalf("expected one 100-sample speech sound, got %d sounds", len(sounds))
+	}
+	if text, _ := os.ReadFile(filepath.Join(dir, "text")); string(text) != "Round 3 of 5" {
+		t.Errorf("expected the phrase on stdin, got %q", text)
+	}
+}
+
+// stoppable is a Recorder that can also be cut off.
+type stoppable struct {
+	Recorder
+	stops int
+}
+
+func (s *stoppable) Stop() { s.stops++ }
+
+func pcmOf(samples ...int16) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, v := range samples {
+		binary.LittleEndian.PutUint16(buf[i*2:], uint16(v))
+	}
+	return buf
+}
+
+func samplesOf(pcm []byte) []int16 {
+	out := make([]int16, len(pcm)/2)
+	for i := range out {
+		out[i] = int16(binary.LittleEndian.Uint16(pcm[i*2:]))
+	}
+	return out
+}
+
+func TestMixerVolumeAndGain(t *testing.T) {
+	var rec Recorder
+	m := NewMixer(&rec, OverlapMix)
+	m.SetVolume(50)
+	m.SetGain("quiet", 0.5)
+	m.SetGain("off", 0)
+
+	m.Play(Sound{Name: "loud", PCM: pcmOf(1000)})
+	m.now = func() time.Time { return time.Now().Add(time.Second) } // let it finish
+	m.Play(Sound{Name: "quiet", PCM: pcmOf(1000)})
+	m.Play(Sound{Name: "off", PCM: pcmOf(1000)})
+
+	sounds := rec.Sounds()
+	if len(sounds) != 2 {
+		t.Fatalf("expected the silenced cue to be skipped, got %v", rec.Played())
+	}
+	if got := samplesOf(sounds[0].PCM)[0]; got != 500 {
+		t.Errorf("expected 500 at half volume, got %d", got)
+	}
+	if got := samplesOf(sounds[1].PCM)[0]; got != 250 {
+		t.Errorf("expected 250 with a 0.5 gain, got %d", got)
+	}
+	if v := m.SetVolume(150); v != 100 {
+		t.Errorf("expected volume clamped to 100, got %d", v)
+	}
+}
+
+func TestMixerOverlap(t *testing.T) {
+	long := Sound{Name: "first", PCM: pcmOf(30000, 30000, 30000, 30000)}
+	short := Sound{Name: "second", PCM: pcmOf(30000, 30000)}
+	var now time.Time
+	newMixer := func(overlap Overlap) (*Mixer, *stoppable) {
+		out := &stoppable{}
+		m := NewMixer(out, overlap)
+		m.now = func() time.Time { return now }
+		return m, out
+	}
+	// Every policy is checked two samples into the first sound.
+	play := func(m *Mixer) {
+		now = time.Now()
+		m.Play(long)
+		now = now.Add(2*time.Second/SampleRate + time.Microsecond)
+		m.Play(short)
+	}
+
+	m, out := newMixer(OverlapMix)
+	play(m)
+	sounds := out.Sounds()
+	if out.stops != 1 || len(sounds) != 2 {
+		t.Fatalf("expected the first sound to be stopped and replaced by a mix, got %d stops, %v", out.stops, out.Played())
+	}
+	if got := samplesOf(sounds[1].PCM); len(got) != 2 || got[0] != math.MaxInt16 {
+		t.Errorf("expected the remaining two samples mixed and scaled to full scale, got %v", got)
+	}
+
+	m, out = newMixer(OverlapReplace)
+	play(m)
+	if sounds := out.Sounds(); out.stops != 1 || len(sounds) != 2 || len(sounds[1].PCM) != len(short.PCM) {
+		t.Errorf("expected the second sound to replace the first, got %d stops, %v", out.stops, out.Played())
+	}
+
+	m, out = newMixer(OverlapQueue)
+	play(m)
+	if got := out.Played(); out.stops != 0 || len(got) != 1 {
+		t.Fatalf("expected the second sound to wait, got %v", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(out.Played()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := out.Played(); len(got) != 2 || got[1] != "second" {
+		t.Errorf("expected the queued sound to play after the first, got %v", got)
+	}
+}
+
+func TestParseOverlap(t *testing.T) {
+	for s, want := range map[string]Overlap{"": OverlapMix, "mix": OverlapMix, "queue": OverlapQueue, "replace": OverlapReplace} {
+		if got, err := ParseOverlap(s); err != nil || got != want {
+			t.Errorf("ParseOverlap(%q): expected %v, got %v (%v)", s, want, got, err)
+		}
+	}
+	if _, err := ParseOverlap("stack"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
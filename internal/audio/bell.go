@@ -0,0 +1,21 @@
+package audio
+
+import (
+	"io"
+	"os"
+)
+
+// Bell rings the terminal bell. It writes to stderr so the bell reaches
+// the terminal without going through the TUI renderer.
+type Bell struct {
+	// Out overrides where the bell character is written; nil means stderr.
+	Out io.Writer
+}
+
+func (b Bell) Play(Sound) {
+	out := b.Out
+	if out == nil {
+		out = os.Stderr
+	}
+	io.WriteString(out, "\a")
+}
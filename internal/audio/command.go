@@ -0,0 +1,51 @@
+package audio
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"strconv"
+)
+
+// Command plays sounds by piping raw PCM to an external program's stdin,
+// e.g. paplay or aplay.
+type Command struct {
+	Name string
+	Args []string
+}
+
+// Paplay plays through PulseAudio / PipeWire.
+func Paplay() Command {
+	return Command{Name: "paplay", Args: []string{
+		"--raw", "--format=s16le", "--rate=" + strconv.Itoa(SampleRate), "--channels=1",
+	}}
+}
+
+// Aplay plays through ALSA.
+func Aplay() Command {
+	return Command{Name: "aplay", Args: []string{
+		"-q", "-t", "raw", "-f", "S16_LE", "-r", strconv.Itoa(SampleRate), "-c", "1",
+	}}
+}
+
+// Shell runs command with `sh -c`; it must read 16-bit little-endian mono
+// PCM at SampleRate from stdin.
+func Shell(command string) Command {
+	return Command{Name: "sh", Args: []string{"-c", command}}
+}
+
+// DetectCommand returns the first of paplay and aplay found on $PATH.
+func DetectCommand() (Command, error) {
+	for _, c := range []Command{Paplay(), Aplay()} {
+		if _, err := exec.LookPath(c.Name); err == nil {
+			return c, nil
+		}
+	}
+	return Command{}, errors.New("neither paplay nor aplay found on $PATH")
+}
+
+func (c Command) Play(s Sound) {
+	cmd := exec.Command(c.Name, c.Args...)
+	cmd.Stdin = bytes.NewReader(s.PCM)
+	go cmd.Run()
+}
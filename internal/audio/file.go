@@ -0,0 +1,200 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/jfreymuth/oggvorbis"
+)
+
+// IsSoundFile reports whether name looks like a file LoadFile can decode,
+// judging by its extension.
+func IsSoundFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".wav", ".ogg":
+		return true
+	}
+	return false
+}
+
+var (
+	fileCacheMu sync.Mutex
+	fileCache   = map[string][]byte{}
+)
+
+// LoadFile decodes the WAV or Ogg Vorbis file at path into a Sound, mixed
+// down to mono and resampled to SampleRate. Decoded PCM is cached by path,
+// so loading the same file for several cues decodes it once.
+func LoadFile(name, path string) (Sound, error) {
+	fileCacheMu.Lock()
+	defer fileCacheMu.Unlock()
+	if pcm, ok := fileCache[path]; ok {
+		return Sound{Name: name, PCM: pcm}, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Sound{}, err
+	}
+	pcm, err := decode(strings.ToLower(filepath.Ext(path)), data)
+	if err != nil {
+		return Sound{}, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	fileCache[path] = pcm
+	return Sound{Name: name, PCM: pcm}, nil
+}
+
+// decode converts a file's contents, identified by its extension, to PCM
+// in the Sound format.
+func decode(ext string, data []byte) ([]byte, error) {
+	var (
+		samples  []float32
+		rate     int
+		channels int
+		err      error
+	)
+	switch ext {
+	case ".wav":
+		samples, rate, channels, err = decodeWAV(data)
+	case ".ogg":
+		var format *oggvorbis.Format
+		samples, format, err = oggvorbis.ReadAll(bytes.NewReader(data))
+		if format != nil {
+			rate, channels = format.SampleRate, format.Channels
+		}
+	default:
+		err = errors.New("unsupported format: expected .wav or .ogg")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if rate <= 0 || channels <= 0 {
+		return nil, errors.New("invalid sample rate or channel count")
+	}
+	return encodePCM(resample(downmix(samples, channels), rate, SampleRate)), nil
+}
+
+// decodeWAV reads a RIFF WAVE file holding integer PCM (8, 16, 24 or 32
+// bit) or IEEE float samples, returning them interleaved in [-1, 1].
+func decodeWAV(data []byte) (samples []float32, rate, channels int, err error) {
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return nil, 0, 0, errors.New("not a WAV file")
+	}
+
+	var format, bits int
+	var pcm []byte
+	for rest := data[12:]; len(rest) >= 8; {
+		id := string(rest[0:4])
+		size := int(binary.LittleEndian.Uint32(rest[4:8]))
+		rest = rest[8:]
+		if size > len(rest) {
+			size = len(rest) // tolerate truncated data chunks
+		}
+		chunk := rest[:size]
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return nil, 0, 0, errors.New("short fmt chunk")
+			}
+			format = int(binary.LittleEndian.Uint16(chunk[0:2]))
+			channels = int(binary.LittleEndian.Uint16(chunk[2:4]))
+			rate = int(binary.LittleEndian.Uint32(chunk[4:8]))
+			bits = int(binary.LittleEndian.Uint16(chunk[14:16]))
+			// WAVE_FORMAT_EXTENSIBLE keeps the real format in its sub-format GUID.
+			if format == 0xFFFE && size >= 26 {
+				format = int(binary.LittleEndian.Uint16(chunk[24:26]))
+			}
+		case "data":
+			pcm = chunk
+		}
+		// chunks are padded to an even length
+		rest = rest[min(size+size%2, len(rest)):]
+	}
+	if format == 0 {
+		return nil, 0, 0, errors.New("missing fmt chunk")
+	}
+	if pcm == nil {
+		return nil, 0, 0, errors.New("missing data chunk")
+	}
+
+	width := bits / 8
+	if width == 0 {
+		return nil, 0, 0, fmt.Errorf("unsupported sample size %d", bits)
+	}
+	samples = make([]float32, len(pcm)/width)
+	for i := range samples {
+		b := pcm[i*width : (i+1)*width]
+		switch {
+		case format == 1 && bits == 8:
+			samples[i] = (float32(b[0]) - 128) / 128
+		case format == 1 && bits == 16:
+			samples[i] = float32(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+		case format == 1 && bits == 24:
+			v := int32(b[0])<<8 | int32(b[1])<<16 | int32(b[2])<<24
+			samples[i] = float32(v>>8) / (1 << 23)
+		case format == 1 && bits == 32:
+			samples[i] = float32(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+		case format == 3 && bits == 32:
+			samples[i] = math.Float32frombits(binary.LittleEndian.Uint32(b))
+		case format == 3 && bits == 64:
+			samples[i] = float32(math.Float64frombits(binary.LittleEndian.Uint64(b)))
+		default:
+			return nil, 0, 0, fmt.Errorf("unsupported encoding (format %d, %d bit)", format, bits)
+		}
+	}
+	return samples, rate, channels, nil
+}
+
+// downmix averages interleaved channels into one.
+func downmix(samples []float32, channels int) []float32 {
+	if channels == 1 {
+		return samples
+	}
+	mono := make([]float32, len(samples)/channels)
+	for i := range mono {
+		var sum float32
+		for c := 0; c < channels; c++ {
+			sum += samples[i*channels+c]
+		}
+		mono[i] = sum / float32(channels)
+	}
+	return mono
+}
+
+// resample converts mono samples between rates by linear interpolation,
+// which is plenty for short cues.
+func resample(samples []float32, from, to int) []float32 {
+	if from == to || len(samples) == 0 {
+		return samples
+	}
+	n := int(int64(len(samples)) * int64(to) / int64(from))
+	out := make([]float32, n)
+	step := float64(from) / float64(to)
+	for i := range out {
+		pos := float64(i) * step
+		j := int(pos)
+		frac := float32(pos - float64(j))
+		next := samples[min(j+1, len(samples)-1)]
+		out[i] = samples[j]*(1-frac) + next*frac
+	}
+	return out
+}
+
+// encodePCM converts samples in [-1, 1] to 16-bit signed little-endian PCM.
+func encodePCM(samples []float32) []byte {
+	buf := make([]byte, len(samples)*2)
+	for i, s := range samples {
+		s = max(-1, min(1, s))
+		val := int16(s * math.MaxInt16)
+		buf[i*2] = byte(val)
+		buf[i*2+1] = byte(val >> 8)
+	}
+	return buf
+}
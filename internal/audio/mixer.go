@@ -0,0 +1,190 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Overlap is what the Mixer does with a sound that arrives while another
+// is still playing.
+type Overlap int
+
+const (
+	OverlapMix     Overlap = iota // sum the two, scaled down if they would clip
+	OverlapQueue                  // play the new sound when the current one ends
+	OverlapReplace                // cut the current sound off
+)
+
+// ParseOverlap converts a config value into an Overlap.
+func ParseOverlap(s string) (Overlap, error) {
+	switch s {
+	case "", "mix":
+		return OverlapMix, nil
+	case "queue":
+		return OverlapQueue, nil
+	case "replace":
+		return OverlapReplace, nil
+	}
+	return 0, fmt.Errorf("invalid audio_overlap %q: expected mix, queue or replace", s)
+}
+
+// Stopper is implemented by players that can cut off whatever they are
+// playing. The Mixer needs it to replace or mix into a playing sound;
+// with other players those policies fall back to playing on top.
+type Stopper interface {
+	Stop()
+}
+
+// Mixer sits in front of a backend, applying the master volume and per-cue
+// gain and deciding what happens when cues overlap. It is a Player itself
+// and safe to use from any goroutine.
+type Mixer struct {
+	out     Player
+	overlap Overlap
+
+	mu      sync.Mutex
+	volume  int                // percent
+	gains   map[string]float64 // by Sound.Name
+	current []byte             // PCM last handed to out
+	started time.Time
+	queue   []Sound
+	timer   *time.Timer
+	now     func() time.Time
+}
+
+func NewMixer(out Player, overlap Overlap) *Mixer {
+	return &Mixer{out: out, overlap: overlap, volume: 100, gains: map[string]float64{}, now: time.Now}
+}
+
+// SetVolume sets the master volume, clamped to 0–100%, and returns it.
+func (m *Mixer) SetVolume(percent int) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.volume = max(0, min(100, percent))
+	return m.volume
+}
+
+// Volume returns the master volume in percent.
+func (m *Mixer) Volume() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.volume
+}
+
+// SetGain scales sounds with the given name, on top of the master volume.
+func (m *Mixer) SetGain(name string, gain float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.gains[name] = gain
+}
+
+func (m *Mixer) Play(s Sound) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	gain := float64(m.volume) / 100
+	if g, ok := m.gains[s.Name]; ok {
+		gain *= g
+	}
+	if gain <= 0 {
+		return
+	}
+	s.PCM = scalePCM(s.PCM, gain)
+
+	rest := m.remaining()
+	if len(rest) == 0 && len(m.queue) == 0 {
+		m.start(s)
+		return
+	}
+	stopper, canStop := m.out.(Stopper)
+	switch {
+	case m.overlap == OverlapQueue:
+		m.queue = append(m.queue, s)
+		if m.timer == nil {
+			m.timer = time.AfterFunc(pcmDuration(rest), m.next)
+		}
+	case m.overlap == OverlapReplace && canStop:
+		stopper.Stop()
+		m.start(s)
+	case m.overlap == OverlapMix && canStop:
+		stopper.Stop()
+		m.start(Sound{Name: s.Name, PCM: mixPCM(rest, s.PCM)})
+	default:
+		m.start(s)
+	}
+}
+
+// next plays the first queued sound and schedules the one after it.
+func (m *Mixer) next() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.timer = nil
+	if len(m.queue) == 0 {
+		return
+	}
+	s := m.queue[0]
+	m.queue = m.queue[1:]
+	m.start(s)
+	if len(m.queue) > 0 {
+		m.timer = time.AfterFunc(pcmDuration(s.PCM), m.next)
+	}
+}
+
+func (m *Mixer) start(s Sound) {
+	m.current = s.PCM
+	m.started = m.now()
+	m.out.Play(s)
+}
+
+// remaining returns the part of the current sound not yet played, judging
+// by how long ago it started.
+func (m *Mixer) remaining() []byte {
+	elapsed := m.now().Sub(m.started)
+	offset := int(elapsed.Seconds()*SampleRate) * 2
+	if offset >= len(m.current) {
+		return nil
+	}
+	return m.current[offset:]
+}
+
+func pcmDuration(pcm []byte) time.Duration {
+	return time.Duration(len(pcm)/2) * time.Second / SampleRate
+}
+
+// scalePCM returns a copy of pcm with every sample multiplied by gain,
+// clipped to the 16-bit range.
+func scalePCM(pcm []byte, gain float64) []byte {
+	if gain == 1 {
+		return pcm
+	}
+	out := make([]byte, len(pcm))
+	for i := 0; i+1 < len(pcm); i += 2 {
+		v := float64(int16(binary.LittleEndian.Uint16(pcm[i:]))) * gain
+		v = max(math.MinInt16, min(math.MaxInt16, v))
+		binary.LittleEndian.PutUint16(out[i:], uint16(int16(v)))
+	}
+	return out
+}
+
+// mixPCM sums two sounds sample by sample. If the sum would clip, the
+// whole mix is scaled down to fit rather than distorting.
+func mixPCM(a, b []byte) []byte {
+	n := max(len(a), len(b)) / 2
+	sum := make([]float64, n)
+	peak := float64(math.MaxInt16)
+	for _, pcm := range [][]byte{a, b} {
+		for i := 0; i < len(pcm)/2; i++ {
+			sum[i] += float64(int16(binary.LittleEndian.Uint16(pcm[i*2:])))
+			peak = max(peak, math.Abs(sum[i]))
+		}
+	}
+	scale := math.MaxInt16 / peak
+	out := make([]byte, n*2)
+	for i, v := range sum {
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(int16(v*scale)))
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package audio
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	oto "github.com/ebitengine/oto/v3"
+)
+
+var (
+	otoCtx  *oto.Context
+	otoOnce sync.Once
+)
+
+func initOto() {
+	ctx, readyChan, err := oto.NewContext(&oto.NewContextOptions{
+		SampleRate:   SampleRate,
+		ChannelCount: 1,
+		Format:       oto.FormatSignedInt16LE,
+	})
+	if err != nil {
+		return
+	}
+	<-readyChan
+	otoCtx = ctx
+}
+
+// Oto plays sounds through the system audio device. The context is shared
+// and created on first use.
+type Oto struct {
+	// Fallback, if set, plays the sound when no audio device is available.
+	Fallback Player
+
+	mu      sync.Mutex
+	playing map[*oto.Player]bool
+	stops   int // Stop calls so far; a play queued before one is dropped
+}
+
+func (o *Oto) Play(s Sound) {
+	o.mu.Lock()
+	stops := o.stops
+	o.mu.Unlock()
+	go func() {
+		otoOnce.Do(initOto)
+		if otoCtx == nil {
+			if o.Fallback != nil && !o.stopped(stops) {
+				o.Fallback.Play(s)
+			}
+			return
+		}
+		player := otoCtx.NewPlayer(bytes.NewReader(s.PCM))
+		if !o.start(player, stops) {
+			player.Close()
+			return
+		}
+		for player.IsPlaying() {
+			time.Sleep(time.Millisecond)
+		}
+		o.mu.Lock()
+		delete(o.playing, player)
+		o.mu.Unlock()
+		player.Close()
+	}()
+}
+
+// stopped reports whether Stop has been called since a play was queued
+// with the count stops.
+func (o *Oto) stopped(stops int) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.stops != stops
+}
+
+// start tracks and plays player, unless Stop has been called since it was
+// queued. Both happen under the lock, so a Stop cannot slip in between.
+func (o *Oto) start(player *oto.Player, stops int) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.stops != stops {
+		return false
+	}
+	if o.playing == nil {
+		o.playing = map[*oto.Player]bool{}
+	}
+	o.playing[player] = true
+	player.Play()
+	return true
+}
+
+// Stop cuts off every sound that is playing, including any queued by a
+// Play that has not started yet.
+func (o *Oto) Stop() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.stops++
+	for player := range o.playing {
+		player.Pause()
+	}
+}
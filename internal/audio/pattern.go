@@ -0,0 +1,59 @@
+package audio
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tone is one step of a cue pattern: a sine at Freq Hz for Duration, or
+// silence when Freq is 0.
+type Tone struct {
+	Freq     float64
+	Duration time.Duration
+}
+
+// ParsePattern parses a space-separated list of <hz>:<ms> tones, e.g.
+// "880:150 0:80 880:150" for a double beep. A frequency of 0 is a rest.
+func ParsePattern(pattern string) ([]Tone, error) {
+	fields := strings.Fields(pattern)
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("empty pattern")
+	}
+	tones := make([]Tone, 0, len(fields))
+	for _, f := range fields {
+		freq, ms, ok := strings.Cut(f, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid tone %q: expected <hz>:<ms>", f)
+		}
+		hz, err := strconv.ParseFloat(freq, 64)
+		if err != nil || hz < 0 {
+			return nil, fmt.Errorf("invalid frequency in %q", f)
+		}
+		n, err := strconv.Atoi(ms)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid duration in %q", f)
+		}
+		tones = append(tones, Tone{Freq: hz, Duration: time.Duration(n) * time.Millisecond})
+	}
+	return tones, nil
+}
+
+// Synthesize renders tones back to back into a Sound.
+func Synthesize(name string, tones []Tone) Sound {
+	var pcm []byte
+	for _, t := range tones {
+		if t.Freq == 0 {
+			pcm = append(pcm, silence(t.Duration.Seconds(), SampleRate)...)
+			continue
+		}
+		pcm = append(pcm, generateSine(t.Freq, t.Duration.Seconds(), SampleRate)...)
+	}
+	return Sound{Name: name, PCM: pcm}
+}
+
+// silence returns duration seconds of zeroed PCM.
+func silence(duration float64, sampleRate int) []byte {
+	return make([]byte, int(float64(sampleRate)*duration)*2)
+}
@@ -0,0 +1,33 @@
+package audio
+
+import "sync"
+
+// Recorder keeps every sound it is asked to play, for tests.
+type Recorder struct {
+	mu     sync.Mutex
+	played []Sound
+}
+
+func (r *Recorder) Play(s Sound) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.played = append(r.played, s)
+}
+
+// Played returns the names of the sounds played so far, in order.
+func (r *Recorder) Played() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, len(r.played))
+	for i, s := range r.played {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// Sounds returns the sounds played so far, in order.
+func (r *Recorder) Sounds() []Sound {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Sound(nil), r.played...)
+}
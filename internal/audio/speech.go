@@ -0,0 +1,76 @@
+package audio
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// speechTimeout bounds how long the TTS command may take for one phrase.
+const speechTimeout = 10 * time.Second
+
+// Speaker turns text into speech with a local TTS command and plays the
+// result through a Player. The command is run with `sh -c`, gets the
+// text on stdin and must write a WAV file to stdout, e.g.
+//
+//	espeak-ng --stdout
+//	piper --model en_US-amy-medium.onnx --output_file -
+//
+// Rendered phrases are cached, since workouts repeat the same few.
+type Speaker struct {
+	command string
+	player  Player
+
+	mu    sync.Mutex
+	cache map[string][]byte
+}
+
+func NewSpeaker(command string, player Player) *Speaker {
+	return &Speaker{command: command, player: player, cache: map[string][]byte{}}
+}
+
+// Say speaks text in the background and returns immediately. If the
+// command fails nothing is played.
+func (s *Speaker) Say(text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	go func() {
+		pcm, err := s.render(text)
+		if err != nil {
+			return
+		}
+		s.player.Play(Sound{Name: "speech", PCM: pcm})
+	}()
+}
+
+func (s *Speaker) render(text string) ([]byte, error) {
+	s.mu.Lock()
+	pcm, ok := s.cache[text]
+	s.mu.Unlock()
+	if ok {
+		return pcm, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), speechTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "sh", "-c", s.command)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.WaitDelay = time.Second
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	pcm, err = decode(".wav", out)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	s.cache[text] = pcm
+	s.mu.Unlock()
+	return pcm, nil
+}
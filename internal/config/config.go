@@ -1,15 +1,74 @@
 package config
 
-import "github.com/BobbyGerace/workout-timer/internal/types"
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
 
 type Config struct {
-	DefaultMode    types.Mode
-	LowTimeWarning int               // seconds, default 30
-	TimeIncrement  int               // seconds, default 30
-	Beep           bool              // default true
-	Keybindings    map[string]string // key → command string
-	FIFOPath       string            // default /tmp/workout-timer.fifo
-	LockPath       string            // default /tmp/workout-timer.lock
+	DefaultMode    types.Mode         `toml:"default_mode"`
+	LowTimeWarning int                `toml:"low_time_warning"` // seconds, default 30
+	TimeIncrement  int                `toml:"time_increment"`   // seconds, default 30
+	Beep           bool               `toml:"beep"`             // start unmuted, default true
+	QuietHours     string             `toml:"quiet_hours"`      // "HH:MM-HH:MM" when cues flash instead of sounding
+	Audio          string             `toml:"audio"`            // backend: auto, oto, bell, command, none; default auto
+	AudioCommand   string             `toml:"audio_command"`    // player for the command backend, default paplay/aplay
+	Volume         int                `toml:"volume"`           // master volume in percent, default 100
+	AudioOverlap   string             `toml:"audio_overlap"`    // overlapping cues: mix, queue or replace; default mix
+	Gains          map[string]float64 `toml:"gains"`            // cue name → gain on top of the volume, default 1
+	FlashDuration  int                `toml:"flash_duration"`   // ms the digits flash when an interval ends, 0 disables; default 1000
+	Urgency        string             `toml:"urgency"`          // terminal alert on interval end: none, bell, notify (OSC 777) or both; default none
+	Cues           map[string]string  `toml:"cues"`             // event → "<hz>:<ms> ..." tone pattern; "" silences
+	CountdownPips  int                `toml:"countdown_pips"`   // pips in the last seconds of an interval, 0 disables; default 3
+	CountdownPitch int                `toml:"countdown_pitch"`  // Hz, default 1000
+	Font           string             `toml:"font"`             // bevelled, square, powerline or a font in fonts/; default bevelled
+	Timeline       int                `toml:"timeline"`         // upcoming intervals listed under the digits, 0 hides the strip; default 3
+	Theme          string             `toml:"theme"`            // dark, light, auto, high-contrast or colorblind; default dark
+	Background     bool               `toml:"background"`       // fill the screen with the phase colour, default false
+	Colors         map[string]string  `toml:"colors"`           // timer, low_time, overflow, done, ready, error, work, rest → colour over the theme's
+	LabelColors    map[string]string  `toml:"label_colors"`     // interval label → colour: 0-255, #rrggbb or light/dark
+	Keybindings    map[string]string  `toml:"keybindings"`      // key → command string; ";" separates a chain of commands
+	Macros         map[string]string  `toml:"macros"`           // name → command chain run when the name is used as a command
+	HistorySize    int                `toml:"history_size"`     // prompt commands kept across sessions, 0 turns history off; default 500
+	FIFOPath       string             `toml:"fifo_path"`        // default /tmp/workout-timer.fifo
+	LockPath       string             `toml:"lock_path"`        // default /tmp/workout-timer.lock
+	SocketPath     string             `toml:"socket_path"`      // default /tmp/workout-timer.sock
+	HTTPPort       int                `toml:"http_port"`        // 0 disables the HTTP API, default 0
+	Neovim         bool               `toml:"neovim"`           // connect to Neovim over RPC, default false
+	NeovimAddress  string             `toml:"neovim_address"`   // default $NVIM
+	Hooks          Hooks              `toml:"hooks"`
+	Speech         Speech             `toml:"speech"`
+	Precision      Precision          `toml:"precision"`
+}
+
+// Hooks are shell commands run when workout events happen. Empty commands
+// are skipped.
+type Hooks struct {
+	OnIntervalStart string `toml:"on_interval_start"`
+	OnZero          string `toml:"on_zero"`
+	OnDone          string `toml:"on_done"`
+	Timeout         int    `toml:"timeout"` // seconds before a hook is killed, default 10
+}
+
+// Speech configures spoken announcements. They are off unless Command is
+// set.
+type Speech struct {
+	Command string            `toml:"command"` // TTS command: text on stdin, WAV on stdout
+	Phrases map[string]string `toml:"phrases"` // event → phrase template; "" is silent
+}
+
+// Precision sets how many decimal places of a second (0, 1 or 2) the big
+// digits show for each kind of program.
+type Precision struct {
+	Timer     int `toml:"timer"`     // default 0
+	Stopwatch int `toml:"stopwatch"` // default 1
+	Final     int `toml:"final"`     // in a countdown's last 10 seconds, if more than Timer; default 0
 }
 
 func Default() Config {
@@ -18,6 +77,16 @@ func Default() Config {
 		LowTimeWarning: 30,
 		TimeIncrement:  30,
 		Beep:           true,
+		Audio:          "auto",
+		Volume:         100,
+		AudioOverlap:   "mix",
+		FlashDuration:  1000,
+		Font:           "bevelled",
+		Timeline:       3,
+		Theme:          "dark",
+		HistorySize:    500,
+		CountdownPips:  3,
+		CountdownPitch: 1000,
 		Keybindings: map[string]string{
 			"space": "pause",
 			"p":     "pause",
@@ -41,7 +110,84 @@ func Default() Config {
 			"9":		 "set 9:00",
 			"0":		 "set 10:00",
 		},
-		FIFOPath: "/tmp/workout-timer.fifo",
-		LockPath: "/tmp/workout-timer.lock",
+		Cues: map[string]string{
+			"interval_start": "660:150 0:60 880:300",
+			"zero":           "880:500",
+			"round_complete": "880:150 0:80 880:150",
+			"done":           "880:150 0:80 880:150 0:80 1320:600",
+			"low_time":       "440:120",
+		},
+		FIFOPath:   "/tmp/workout-timer.fifo",
+		LockPath:   "/tmp/workout-timer.lock",
+		SocketPath: "/tmp/workout-timer.sock",
+		Hooks: Hooks{
+			Timeout: 10,
+		},
+		Speech: Speech{
+			Phrases: map[string]string{
+				"interval_start": "#{label} #{duration}",
+				"halfway":        "Halfway",
+				"round_complete": "Round #{round} complete",
+				"done":           "Workout complete",
+			},
+		},
+		Precision: Precision{
+			Stopwatch: 1,
+		},
+	}
+}
+
+// Dir returns the directory holding config.toml and any other user files,
+// honouring $XDG_CONFIG_HOME and falling back to ~/.config/workout-timer.
+func Dir() string {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "workout-timer")
+}
+
+// StateDir returns the directory for files the timer writes itself, such
+// as the prompt history, honouring $XDG_STATE_HOME and falling back to
+// ~/.local/state/workout-timer.
+func StateDir() string {
+	base := os.Getenv("XDG_STATE_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		base = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(base, "workout-timer")
+}
+
+// Load reads config.toml from Dir and applies it over the defaults.
+// A missing file is not an error; the defaults are returned unchanged.
+func Load() (Config, error) {
+	return LoadFile(filepath.Join(Dir(), "config.toml"))
+}
+
+// LoadFile reads the TOML file at path and applies it over the defaults.
+// Keys absent from the file keep their default values, and a keybinding
+// set to "" unsets that key.
+func LoadFile(path string) (Config, error) {
+	cfg := Default()
+	_, err := toml.DecodeFile(path, &cfg)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	for key, command := range cfg.Keybindings {
+		if command == "" {
+			delete(cfg.Keybindings, key)
+		}
 	}
+	return cfg, nil
 }
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileMissingReturnsDefaults(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join(t.TempDir(), "nope.toml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.LowTimeWarning != 30 || cfg.Keybindings["space"] != "pause" {
+		t.Errorf("expected defaults, got %+v", cfg)
+	}
+}
+
+func TestLoadFileOverridesDefaults(t *testing.T) {
+	path := writeConfig(t, `
+default_mode = "manual"
+low_time_warning = 10
+http_port = 8080
+
+[keybindings]
+x = "reset"
+b = ""
+`)
+	cfg, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.DefaultMode != types.ModeManual {
+		t.Errorf("expected manual mode, got %v", cfg.DefaultMode)
+	}
+	if cfg.LowTimeWarning != 10 {
+		t.Errorf("expected low_time_warning 10, got %d", cfg.LowTimeWarning)
+	}
+	if cfg.TimeIncrement != 30 {
+		t.Errorf("unset key should keep default, got %d", cfg.TimeIncrement)
+	}
+	if cfg.HTTPPort != 8080 {
+		t.Errorf("expected http_port 8080, got %d", cfg.HTTPPort)
+	}
+	if cfg.Keybindings["x"] != "reset" {
+		t.Errorf("expected x bound to reset, got %q", cfg.Keybindings["x"])
+	}
+	if _, ok := cfg.Keybindings["b"]; ok {
+		t.Error(`"b" = "" should unset the binding`)
+	}
+	if cfg.Keybindings["space"] != "pause" {
+		t.Errorf("default bindings should be kept, got %q", cfg.Keybindings["space"])
+	}
+}
+
+func TestLoadFileInvalidMode(t *testing.T) {
+	path := writeConfig(t, `default_mode = "sideways"`)
+	if _, err := LoadFile(path); err == nil {
+		t.Error("expected error for invalid mode")
+	}
+}
@@ -0,0 +1,52 @@
+// Package fifo listens on a named pipe for commands, one per line.
+package fifo
+
+import (
+	"bufio"
+	"os"
+	"strings"
+
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// Listener reads commands written to the FIFO, e.g.
+// `echo next > /tmp/workout-timer.fifo`. Nobody waits for a reply, so
+// invalid commands are dropped.
+type Listener struct {
+	path        string
+	dispatch    func(commands parser.Chain)
+	defaultMode types.Mode
+	macros      map[string]string
+	file        *os.File
+}
+
+// New returns a Listener for the FIFO at path. dispatch has the same
+// contract as for the other listeners: it must be safe to call from any
+// goroutine.
+func New(path string, defaultMode types.Mode, macros map[string]string, dispatch func(commands parser.Chain)) *Listener {
+	return &Listener{path: path, dispatch: dispatch, defaultMode: defaultMode, macros: macros}
+}
+
+// Close stops reading. The FIFO itself is left in place for reuse.
+func (l *Listener) Close() error {
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
+func (l *Listener) read() {
+	lines := bufio.NewScanner(l.file)
+	for lines.Scan() {
+		command := strings.TrimSpace(lines.Text())
+		if command == "" {
+			continue
+		}
+		chain, err := parser.ParseChain(command, l.defaultMode, l.macros)
+		if err != nil {
+			continue
+		}
+		l.dispatch(chain)
+	}
+}
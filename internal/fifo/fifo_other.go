@@ -0,0 +1,15 @@
+//go:build !unix
+
+package fifo
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+)
+
+// Start reports that named pipes are not supported here; use the control
+// socket or the HTTP API instead.
+func (l *Listener) Start() error {
+	return fmt.Errorf("fifo %s: %w on %s", l.path, errors.ErrUnsupported, runtime.GOOS)
+}
@@ -0,0 +1,90 @@
+//go:build unix
+
+package fifo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+func TestFIFODispatchesValidCommands(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timer.fifo")
+	got := make(chan string, 10)
+	l := New(path, types.ModeAuto, nil, func(command parser.Chain) { got <- command.String() })
+	if err := l.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer l.Close()
+
+	w, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open for writing: %v", err)
+	}
+	w.WriteString("fly\n\nadd 30\n")
+	w.Close()
+
+	select {
+	case cmd := <-got:
+		if cmd != "add 30" {
+			t.Errorf("expected add 30, got %q", cmd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for dispatch")
+	}
+
+	// A second writer after the first has gone still gets through.
+	w, _ = os.OpenFile(path, os.O_WRONLY, 0)
+	w.WriteString("next\n")
+	w.Close()
+	select {
+	case cmd := <-got:
+		if cmd != "next" {
+			t.Errorf("expected next, got %q", cmd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for second dispatch")
+	}
+}
+
+func TestFIFORefusesRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "not-a-fifo")
+	os.WriteFile(path, nil, 0o600)
+
+	l := New(path, types.ModeAuto, nil, func(parser.Chain) {})
+	if err := l.Start(); err == nil {
+		l.Close()
+		t.Error("expected error for a regular file")
+	}
+}
+
+func TestFIFOExpandsMacros(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timer.fifo")
+	got := make(chan string, 10)
+	macros := map[string]string{"warmup": "set auto 5:00 x1; start"}
+	l := New(path, types.ModeAuto, macros, func(command parser.Chain) { got <- command.String() })
+	if err := l.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	defer l.Close()
+
+	w, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("open for writing: %v", err)
+	}
+	w.WriteString("warmup; mute\n")
+	w.Close()
+
+	select {
+	case cmd := <-got:
+		if cmd != "set auto 5:00 x1; start; mute" {
+			t.Errorf("expected the macro expanded in the chain, got %q", cmd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for dispatch")
+	}
+}
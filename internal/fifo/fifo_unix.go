@@ -0,0 +1,35 @@
+//go:build unix
+
+package fifo
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Start creates the FIFO if it doesn't exist (reusing it if it does) and
+// reads from it in a goroutine.
+func (l *Listener) Start() error {
+	info, err := os.Stat(l.path)
+	switch {
+	case os.IsNotExist(err):
+		if err := syscall.Mkfifo(l.path, 0o600); err != nil {
+			return err
+		}
+	case err != nil:
+		return err
+	case info.Mode()&os.ModeNamedPipe == 0:
+		return fmt.Errorf("%s exists and is not a FIFO", l.path)
+	}
+
+	// Opening read-write keeps a writer attached, so the open doesn't block
+	// waiting for one and reads never hit EOF between writers.
+	f, err := os.OpenFile(l.path, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	l.file = f
+	go l.read()
+	return nil
+}
@@ -0,0 +1,82 @@
+// Package hooks runs user-configured shell commands on workout events.
+package hooks
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/model"
+)
+
+// Runner starts the hook command for each event it is handed.
+type Runner struct {
+	commands map[model.EventKind]string
+	timeout  time.Duration
+}
+
+func New(cfg config.Hooks) *Runner {
+	return &Runner{
+		commands: map[model.EventKind]string{
+			model.EventIntervalStart: cfg.OnIntervalStart,
+			model.EventZero:          cfg.OnZero,
+			model.EventDone:          cfg.OnDone,
+		},
+		timeout: time.Duration(cfg.Timeout) * time.Second,
+	}
+}
+
+// Enabled reports whether any hook is configured.
+func (r *Runner) Enabled() bool {
+	for _, command := range r.commands {
+		if command != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle starts the hook for e, if any, and returns immediately. The
+// command runs under `sh -c` with the event described in WORKOUT_TIMER_*
+// environment variables, and is killed, with anything it started, if it
+// outlives the timeout. Its
+// output is discarded so it cannot draw over the TUI.
+func (r *Runner) Handle(e model.Event) {
+	command := r.commands[e.Kind]
+	if command == "" {
+		return
+	}
+	go r.run(command, e)
+}
+
+func (r *Runner) run(command string, e model.Event) {
+	ctx := context.Background()
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(), Env(e)...)
+	killGroupOnCancel(cmd)
+	cmd.WaitDelay = time.Second
+	cmd.Run()
+}
+
+// Env returns the WORKOUT_TIMER_* variables describing e.
+func Env(e model.Event) []string {
+	s := e.Snapshot
+	return []string{
+		"WORKOUT_TIMER_EVENT=" + e.Kind.String(),
+		"WORKOUT_TIMER_STATE=" + s.State,
+		"WORKOUT_TIMER_LABEL=" + s.Label,
+		"WORKOUT_TIMER_REMAINING=" + s.Time,
+		"WORKOUT_TIMER_INTERVAL=" + strconv.Itoa(e.Interval),
+		"WORKOUT_TIMER_INTERVALS=" + strconv.Itoa(max(s.Intervals, 1)),
+		"WORKOUT_TIMER_ROUND=" + strconv.Itoa(e.Round),
+		"WORKOUT_TIMER_ROUNDS=" + strconv.Itoa(s.Rounds),
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package hooks
+
+import "os/exec"
+
+// killGroupOnCancel leaves cmd as it is: without process groups, only the
+// shell itself is killed.
+func killGroupOnCancel(cmd *exec.Cmd) {}
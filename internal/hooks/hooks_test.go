@@ -0,0 +1,73 @@
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/model"
+)
+
+// waitForFile polls until path exists with content, failing on timeout.
+func waitForFile(t *testing.T, path string) string {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return string(data)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("hook never wrote %s", path)
+	return ""
+}
+
+func TestHookReceivesEventEnv(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	r := New(config.Hooks{
+		OnIntervalStart: `echo "$WORKOUT_TIMER_EVENT $WORKOUT_TIMER_LABEL $WORKOUT_TIMER_INTERVAL/$WORKOUT_TIMER_INTERVALS r$WORKOUT_TIMER_ROUND $WORKOUT_TIMER_REMAINING" > ` + out,
+		Timeout:         5,
+	})
+
+	r.Handle(model.Event{
+		Kind:     model.EventIntervalStart,
+		Interval: 2,
+		Round:    3,
+		Snapshot: model.Snapshot{Time: "1:30", Label: "work", Interval: 2, Intervals: 4},
+	})
+
+	got := strings.TrimSpace(waitForFile(t, out))
+	if got != "interval_start work 2/4 r3 1:30" {
+		t.Errorf("unexpected hook output %q", got)
+	}
+}
+
+func TestHandleDoesNotBlock(t *testing.T) {
+	r := New(config.Hooks{OnDone: "sleep 5", Timeout: 1})
+
+	start := time.Now()
+	r.Handle(model.Event{Kind: model.EventDone})
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Handle blocked for %v", elapsed)
+	}
+}
+
+func TestUnconfiguredEventsAreSkipped(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	r := New(config.Hooks{OnDone: "touch " + out})
+
+	r.Handle(model.Event{Kind: model.EventZero})
+	time.Sleep(100 * time.Millisecond)
+	if _, err := os.Stat(out); err == nil {
+		t.Error("zero event should not run the on_done hook")
+	}
+	if !r.Enabled() {
+		t.Error("runner with on_done should be enabled")
+	}
+	if New(config.Hooks{}).Enabled() {
+		t.Error("runner without hooks should not be enabled")
+	}
+}
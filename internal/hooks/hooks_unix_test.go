@@ -0,0 +1,24 @@
+//go:build unix
+
+package hooks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/model"
+)
+
+func TestTimeoutKillsChildren(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	r := New(config.Hooks{OnDone: "(sleep 2; touch " + out + ") & wait", Timeout: 1})
+
+	r.Handle(model.Event{Kind: model.EventDone})
+	time.Sleep(2500 * time.Millisecond)
+	if _, err := os.Stat(out); err == nil {
+		t.Error("expected the hook's children to be killed with it")
+	}
+}
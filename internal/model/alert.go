@@ -0,0 +1,90 @@
+package model
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Urgency selects the escape sequences written to the terminal when an
+// interval ends, so a window manager or tmux can flag the window.
+type Urgency int
+
+const (
+	UrgencyNone   Urgency = iota
+	UrgencyBell           // BEL, which tmux turns into a window flag
+	UrgencyNotify         // OSC 777 desktop notification
+	UrgencyBoth
+)
+
+// ParseUrgency converts a config value into an Urgency.
+func ParseUrgency(s string) (Urgency, error) {
+	switch s {
+	case "", "none":
+		return UrgencyNone, nil
+	case "bell":
+		return UrgencyBell, nil
+	case "notify":
+		return UrgencyNotify, nil
+	case "both":
+		return UrgencyBoth, nil
+	}
+	return 0, fmt.Errorf("invalid urgency %q: expected none, bell, notify or both", s)
+}
+
+// urgencySequence returns the escapes for u announcing body. Inside tmux
+// the notification is wrapped in a passthrough so it reaches the outer
+// terminal.
+func urgencySequence(u Urgency, body string, tmux bool) string {
+	var b strings.Builder
+	if u == UrgencyNotify || u == UrgencyBoth {
+		osc := "\x1b]777;notify;Workout timer;" + body + "\a"
+		if tmux {
+			osc = "\x1bPtmux;" + strings.ReplaceAll(osc, "\x1b", "\x1b\x1b") + "\x1b\\"
+		}
+		b.WriteString(osc)
+	}
+	if u == UrgencyBell || u == UrgencyBoth {
+		b.WriteString("\a")
+	}
+	return b.String()
+}
+
+// alert flashes the digits and returns a command raising terminal urgency
+// for the pending events that end an interval.
+func (m Model) alert() (Model, tea.Cmd) {
+	var body string
+	for _, e := range m.pending {
+		switch e.Kind {
+		case EventZero:
+			body = fmt.Sprintf("Interval %d finished", e.Interval)
+		case EventDone:
+			body = "Workout complete"
+		}
+	}
+	if body == "" {
+		return m, nil
+	}
+	if d := m.flashDuration(); d > 0 {
+		m.flashUntil = m.now().Add(d)
+	}
+	if m.urgency == UrgencyNone || m.urgencyOut == nil {
+		return m, nil
+	}
+	// Written from a command in one call, so the sequence lands between
+	// the renderer's frames rather than inside one.
+	seq := urgencySequence(m.urgency, body, os.Getenv("TMUX") != "")
+	out := m.urgencyOut
+	return m, func() tea.Msg {
+		io.WriteString(out, seq)
+		return nil
+	}
+}
+
+func (m Model) flashDuration() time.Duration {
+	return time.Duration(m.config.FlashDuration) * time.Millisecond
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestUrgencySequence(t *testing.T) {
+	tests := []struct {
+		urgency Urgency
+		tmux    bool
+		want    string
+	}{
+		{UrgencyNone, false, ""},
+		{UrgencyBell, false, "\a"},
+		{UrgencyNotify, false, "\x1b]777;notify;Workout timer;Done\a"},
+		{UrgencyNotify, true, "\x1bPtmux;\x1b\x1b]777;notify;Workout timer;Done\a\x1b\\"},
+		{UrgencyBoth, false, "\x1b]777;notify;Workout timer;Done\a\a"},
+	}
+	for _, tt := range tests {
+		if got := urgencySequence(tt.urgency, "Done", tt.tmux); got != tt.want {
+			t.Errorf("urgency %d (tmux %v): expected %q, got %q", tt.urgency, tt.tmux, tt.want, got)
+		}
+	}
+}
+
+// runCmd runs cmd and any commands it batches, discarding their messages.
+func runCmd(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	if batch, ok := cmd().(tea.BatchMsg); ok {
+		for _, c := range batch {
+			runCmd(c)
+		}
+	}
+}
+
+func TestIntervalEndAlert(t *testing.T) {
+	cfg := config.Default()
+	cfg.Urgency = "bell"
+	m := New(cfg, audio.Silent{})
+	var out bytes.Buffer
+	m.urgencyOut = &out
+
+	start := time.Now()
+	m = send(t, m,
+		commandMsg("set manual 10,10"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(5*time.Second)),
+	)
+	if m.flashing() || out.Len() != 0 {
+		t.Fatalf("expected no alert before the interval ends, got flash %v, %q", m.flashing(), out.String())
+	}
+
+	next, cmd := m.Update(tickMsg(start.Add(10 * time.Second)))
+	m = next.(Model)
+	if !m.flashing() {
+		t.Error("expected the digits to flash when the interval ends")
+	}
+	if out.Len() != 0 {
+		t.Errorf("expected nothing written during Update, got %q", out.String())
+	}
+	runCmd(cmd)
+	if out.String() != "\a" {
+		t.Errorf("expected a bell from the returned command, got %q", out.String())
+	}
+
+	m = send(t, m, tickMsg(start.Add(11500*time.Millisecond)))
+	if m.flashing() {
+		t.Error("expected the flash to end after flash_duration")
+	}
+}
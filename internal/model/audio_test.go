@@ -0,0 +1,151 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestCuesPerEvent(t *testing.T) {
+	var rec audio.Recorder
+	m := New(config.Default(), &rec)
+	start := time.Now()
+	send(t, m,
+		commandMsg("set manual 10,10 x2"),
+		tickMsg(start),
+		commandMsg("start"),                // interval_start
+		tickMsg(start.Add(11*time.Second)), // zero, manual mode waits
+		tickMsg(start.Add(15*time.Second)), // overflow, nothing new
+		commandMsg("next"),                 // interval_start
+		commandMsg("next"),                 // round_complete wins over interval_start
+		commandMsg("next"),                 // interval_start
+		commandMsg("next"),                 // done
+	)
+
+	want := []string{"interval_start", "zero", "interval_start", "round_complete", "interval_start", "done"}
+	if got := rec.Played(); !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestAutoAdvancePlaysOneCue(t *testing.T) {
+	var rec audio.Recorder
+	m := New(config.Default(), &rec)
+	start := time.Now()
+	send(t, m,
+		commandMsg("set auto 10,10"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(11*time.Second)), // zero + interval_start in one tick
+	)
+
+	want := []string{"interval_start", "interval_start"}
+	if got := rec.Played(); !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// Without an interval-start cue the zero cue plays instead.
+	cfg := config.Default()
+	cfg.Cues["interval_start"] = ""
+	rec = audio.Recorder{}
+	send(t, New(cfg, &rec),
+		commandMsg("set auto 10,10"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(11*time.Second)),
+	)
+	if got := rec.Played(); !equalStrings(got, []string{"zero"}) {
+		t.Errorf("expected the zero cue when interval starts are silenced, got %v", got)
+	}
+}
+
+func TestSilencedAndInvalidCues(t *testing.T) {
+	cfg := config.Default()
+	cfg.Cues = map[string]string{
+		"interval_start": "",
+		"zero":           "not-a-pattern",
+		"explosion":      "100:100",
+	}
+	var rec audio.Recorder
+	m := New(cfg, &rec)
+	if !strings.Contains(m.warning, "cue zero") || !strings.Contains(m.warning, `unknown cue "explosion"`) {
+		t.Errorf("expected warning about both cues, got %q", m.warning)
+	}
+
+	start := time.Now()
+	send(t, m,
+		commandMsg("set manual 10"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(11*time.Second)),
+	)
+
+	want := []string{"beep"}
+	if got := rec.Played(); !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestCountdownPips(t *testing.T) {
+	var rec audio.Recorder
+	cfg := config.Default()
+	cfg.CountdownPips = 2
+	m := New(cfg, &rec)
+	start := time.Now()
+	send(t, m,
+		commandMsg("set manual 5"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(2100*time.Millisecond)), // 3s left: beyond the pip count
+		tickMsg(start.Add(3500*time.Millisecond)), // 2s
+		tickMsg(start.Add(4200*time.Millisecond)), // 1s
+		tickMsg(start.Add(5100*time.Millisecond)), // zero
+	)
+
+	want := []string{"interval_start", "countdown", "countdown", "zero"}
+	if got := rec.Played(); !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMissingCueFileFallsBackToBeep(t *testing.T) {
+	cues, warning := loadCues(map[string]string{"done": "gong.wav"}, t.TempDir())
+	if cues[EventDone].Name != "beep" {
+		t.Errorf("expected the beep, got %q", cues[EventDone].Name)
+	}
+	if !strings.Contains(warning, "cue done") {
+		t.Errorf("expected a warning about the done cue, got %q", warning)
+	}
+}
+
+func TestVolumeCommand(t *testing.T) {
+	var rec audio.Recorder
+	cfg := config.Default()
+	cfg.Volume = 80
+	m := New(cfg, &rec)
+
+	m = send(t, m, commandMsg("volume -30"))
+	if v := m.mixer.Volume(); v != 50 {
+		t.Errorf("expected volume 50, got %d", v)
+	}
+
+	m = send(t, m, commandMsg("volume 0"), commandMsg("set 10"), commandMsg("start"))
+	if got := rec.Played(); len(got) != 0 {
+		t.Errorf("expected nothing at volume 0, got %v", got)
+	}
+}
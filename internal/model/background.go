@@ -0,0 +1,123 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// background returns the colour to fill the screen with, or nil when the
+// background mode is off or there is nothing to show.
+func (m Model) background() lipgloss.TerminalColor {
+	if !m.config.Background {
+		return nil
+	}
+	switch m.AppState() {
+	case Unconfigured:
+		return nil
+	case Ready:
+		return m.theme.Ready
+	case Done:
+		return m.theme.Done
+	}
+	if m.prog.IsOverflow() {
+		return m.theme.Overflow
+	}
+	// The digits are drawn to contrast, so the warning moves to the fill.
+	if m.prog.IsLowTime(m.lowTimeThreshold()) {
+		return m.theme.LowTime
+	}
+	return m.phaseColor()
+}
+
+// paint puts style on the phase background, in a colour that contrasts
+// with it. Without a background style is returned unchanged.
+func (m Model) paint(style lipgloss.Style) lipgloss.Style {
+	bg := m.background()
+	if bg == nil {
+		return style
+	}
+	return style.Foreground(contrast(bg)).Background(bg)
+}
+
+// fill pads every line of s to the full width on the phase background.
+func (m Model) fill(s string) string {
+	bg := m.background()
+	if bg == nil {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = lipgloss.PlaceHorizontal(m.width, lipgloss.Left, line, lipgloss.WithWhitespaceBackground(bg))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// contrast returns black or white, whichever reads better on bg.
+func contrast(bg lipgloss.TerminalColor) lipgloss.TerminalColor {
+	switch c := bg.(type) {
+	case lipgloss.AdaptiveColor:
+		return lipgloss.AdaptiveColor{
+			Light: string(contrast(lipgloss.Color(c.Light)).(lipgloss.Color)),
+			Dark:  string(contrast(lipgloss.Color(c.Dark)).(lipgloss.Color)),
+		}
+	case lipgloss.Color:
+		r, g, b, ok := rgb(c)
+		// perceived brightness, ITU-R BT.601
+		if ok && 0.299*r+0.587*g+0.114*b > 140 {
+			return lipgloss.Color("#000000")
+		}
+	}
+	return lipgloss.Color("#ffffff")
+}
+
+// ansiRGB approximates the 16 basic terminal colours (xterm defaults).
+var ansiRGB = [16][3]float64{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// rgb returns the components of a palette index or hex colour.
+func rgb(c lipgloss.Color) (r, g, b float64, ok bool) {
+	if r, g, b, ok := hexRGB(c); ok {
+		return float64(r), float64(g), float64(b), true
+	}
+	n, err := strconv.Atoi(string(c))
+	switch {
+	case err != nil || n < 0 || n > 255:
+		return 0, 0, 0, false
+	case n < 16:
+		return ansiRGB[n][0], ansiRGB[n][1], ansiRGB[n][2], true
+	case n < 232:
+		// 6×6×6 colour cube
+		level := func(i int) float64 {
+			if i == 0 {
+				return 0
+			}
+			return float64(55 + 40*i)
+		}
+		n -= 16
+		return level(n / 36), level(n / 6 % 6), level(n % 6), true
+	}
+	gray := float64(8 + 10*(n-232))
+	return gray, gray, gray, true
+}
+
+// hexRGB splits a "#rgb" or "#rrggbb" colour into its channels.
+func hexRGB(c lipgloss.Color) (r, g, b uint8, ok bool) {
+	hex, ok := strings.CutPrefix(string(c), "#")
+	if !ok {
+		return 0, 0, 0, false
+	}
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if len(hex) != 6 || err != nil {
+		return 0, 0, 0, false
+	}
+	return uint8(n >> 16), uint8(n >> 8), uint8(n), true
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestContrast(t *testing.T) {
+	black, white := lipgloss.Color("#000000"), lipgloss.Color("#ffffff")
+	tests := []struct {
+		bg   lipgloss.TerminalColor
+		want lipgloss.TerminalColor
+	}{
+		{lipgloss.Color("#ffffff"), black},
+		{lipgloss.Color("#0072b2"), white},
+		{lipgloss.Color("#e69f00"), black},
+		{lipgloss.Color("#ff0"), black},
+		{lipgloss.Color("4"), white},
+		{lipgloss.Color("11"), black},
+		{lipgloss.Color("231"), black}, // cube white
+		{lipgloss.Color("17"), white},  // cube navy
+		{lipgloss.Color("232"), white}, // darkest grey
+		{lipgloss.Color("255"), black},
+		{lipgloss.AdaptiveColor{Light: "#d1d5db", Dark: "8"}, lipgloss.AdaptiveColor{Light: "#000000", Dark: "#ffffff"}},
+	}
+	for _, tt := range tests {
+		if got := contrast(tt.bg); got != tt.want {
+			t.Errorf("contrast(%v): expected %v, got %v", tt.bg, tt.want, got)
+		}
+	}
+}
+
+func TestBackgroundByPhase(t *testing.T) {
+	cfg := config.Default()
+	cfg.Background = true
+	m := New(cfg, audio.Silent{})
+	if bg := m.background(); bg != nil {
+		t.Errorf("expected no background before a program is set, got %v", bg)
+	}
+
+	start := time.Now()
+	m = send(t, m, commandMsg("set manual work=40,rest=40 x1"), tickMsg(start))
+	steps := []struct {
+		msgs []any
+		want lipgloss.TerminalColor
+	}{
+		{nil, DarkTheme.Ready},
+		{[]any{commandMsg("start"), tickMsg(start.Add(time.Second))}, DarkTheme.Work},
+		{[]any{tickMsg(start.Add(15 * time.Second))}, DarkTheme.LowTime},
+		{[]any{tickMsg(start.Add(42 * time.Second))}, DarkTheme.Overflow},
+		{[]any{commandMsg("next")}, DarkTheme.Rest},
+		{[]any{tickMsg(start.Add(83 * time.Second))}, DarkTheme.Done},
+	}
+	for i, step := range steps {
+		m = send(t, m, step.msgs...)
+		if got := m.background(); got != step.want {
+			t.Errorf("step %d: expected %v, got %v", i, step.want, got)
+		}
+	}
+
+	m.config.Background = false
+	if bg := m.background(); bg != nil {
+		t.Errorf("expected no background when the mode is off, got %v", bg)
+	}
+}
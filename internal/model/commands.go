@@ -2,96 +2,138 @@ package model
 
 import (
 	"fmt"
-	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/renderer"
+	"github.com/BobbyGerace/workout-timer/internal/stopwatch"
 )
 
-// executeCommand dispatches a command string, returning the updated model,
-// any tea.Cmd to run, and an error suitable for display in the prompt.
-// Errors from keybinding dispatch are silently ignored by the caller.
-func (m Model) executeCommand(command string) (Model, tea.Cmd, error) {
-	command = strings.TrimSpace(command)
-	if command == "" {
-		return m, nil, nil
+// statusFormat is how the status command describes the timer.
+const statusFormat = "#{state} #{remaining} #{progress}"
+
+// runCommand parses and executes a command line, as typed at the prompt
+// or bound to a key.
+func (m Model) runCommand(text string) (Model, tea.Cmd, error) {
+	chain, err := parser.ParseChain(text, m.config.DefaultMode, m.config.Macros)
+	if err != nil {
+		return m, nil, err
 	}
+	return m.executeChain(chain)
+}
 
-	verb := strings.Fields(command)[0]
+// executeChain executes each command in turn, stopping at the first that
+// fails.
+func (m Model) executeChain(chain parser.Chain) (Model, tea.Cmd, error) {
+	var cmds []tea.Cmd
+	for _, c := range chain {
+		var cmd tea.Cmd
+		var err error
+		m, cmd, err = m.executeCommand(c)
+		cmds = append(cmds, cmd)
+		if err != nil {
+			return m, tea.Batch(cmds...), err
+		}
+	}
+	return m, tea.Batch(cmds...), nil
+}
 
-	switch verb {
-	case "quit", "q":
+// executeCommand carries out a parsed command, returning the updated model,
+// any tea.Cmd to run, and an error suitable for display in the prompt.
+// Errors from keybinding dispatch are silently ignored by the caller.
+func (m Model) executeCommand(c parser.Command) (Model, tea.Cmd, error) {
+	switch c.Verb {
+	case parser.VerbQuit:
 		return m, tea.Quit, nil
 
-	case "start":
+	case parser.VerbStart:
 		if m.prog != nil {
 			m.prog.Start()
 		}
 		return m, nil, nil
 
-	case "next":
+	case parser.VerbNext:
 		if m.prog != nil {
 			m.prog.Next()
 		}
 		return m, nil, nil
 
-	case "pause", "resume":
+	case parser.VerbPause, parser.VerbResume:
 		if m.prog != nil {
 			m.prog.TogglePause()
 		}
 		return m, nil, nil
 
-	case "back":
+	case parser.VerbBack:
 		if m.prog != nil {
 			m.prog.Back()
 		}
 		return m, nil, nil
 
-	case "add", "subtract":
-		if m.prog == nil {
-			return m, nil, nil
-		}
-		parts := strings.Fields(command)
-		if len(parts) != 2 {
-			return m, nil, fmt.Errorf("%s requires a duration (e.g. 30 or 1:30)", verb)
-		}
-		d, err := parser.ParseDuration(parts[1])
-		if err != nil || d <= 0 {
-			return m, nil, fmt.Errorf("invalid duration: %q", parts[1])
+	case parser.VerbAdd:
+		if m.prog != nil {
+			m.prog.Add(c.Duration)
 		}
-		if verb == "add" {
-			m.prog.Add(d)
-		} else {
-			m.prog.Subtract(d)
+		return m, nil, nil
+
+	case parser.VerbSubtract:
+		if m.prog != nil {
+			m.prog.Subtract(c.Duration)
 		}
 		return m, nil, nil
 
-	case "reset":
+	case parser.VerbReset:
 		if m.prog != nil {
 			m.prog.Reset()
 			m.completionMsg = ""
 		}
 		return m, nil, nil
 
-	case "clear":
+	case parser.VerbStopwatch:
+		m.prog = stopwatch.New()
+		m.prog.Start()
+		m.completionMsg = ""
+		return m, nil, nil
+
+	case parser.VerbClear:
 		m.prog = nil
 		m.completionMsg = ""
 		return m, nil, nil
 
-	case "prompt":
+	case parser.VerbStatus:
+		m.message = FormatStatus(statusFormat, m.Snapshot())
+		return m, nil, nil
+
+	case parser.VerbHelp:
+		m.showHelp = !m.showHelp
+		return m, nil, nil
+
+	case parser.VerbPrompt:
 		m, cmd := m.openPrompt()
 		return m, cmd, nil
 
-	case "set":
-		p, err := parser.ParseSet(command, m.config.DefaultMode)
-		if err != nil {
-			return m, nil, err
+	case parser.VerbMute, parser.VerbUnmute:
+		m.muted = c.Verb == parser.VerbMute
+		return m, nil, nil
+
+	case parser.VerbFont:
+		font, ok := renderer.Lookup(c.Name)
+		if !ok {
+			return m, nil, unknownFont(c.Name)
 		}
-		m.prog = p
+		m.font = font
+		return m, nil, nil
+
+	case parser.VerbVolume:
+		m.mixer.SetVolume(c.Volume.From(m.mixer.Volume()))
+		return m, nil, nil
+
+	case parser.VerbSet:
+		m.prog = c.Program()
 		m.completionMsg = ""
 		return m, nil, nil
 	}
 
-	return m, nil, fmt.Errorf("unknown command: %s", verb)
+	return m, nil, fmt.Errorf("%s cannot be run here", c.Verb)
 }
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+)
+
+// TestEveryParsableCommandExecutes keeps the parser and executeCommand in
+// step: a command that parses must run, idle or mid-workout, and every
+// verb needs a line here.
+func TestEveryParsableCommandExecutes(t *testing.T) {
+	commands := []string{
+		"quit", "q", "start", "next", "pause", "resume", "back", "reset",
+		"clear", "status", "stopwatch", "mute", "unmute", "help", "prompt",
+		"add 30", "subtract 1:00", "set auto work=45,rest=15 x3",
+		"font square", "volume 80", "volume -10",
+	}
+	idle := New(config.Default(), audio.Silent{})
+	running := send(t, idle, commandMsg("set 1:00"), commandMsg("start"))
+
+	covered := map[parser.Verb]bool{}
+	for _, text := range commands {
+		c, err := parser.Parse(text, idle.config.DefaultMode)
+		if err != nil {
+			t.Errorf("%q: expected to parse, got %v", text, err)
+			continue
+		}
+		covered[c.Verb] = true
+		for _, m := range []Model{idle, running} {
+			if _, _, err := m.executeCommand(c); err != nil {
+				t.Errorf("%q in state %v: expected to execute, got %v", text, m.AppState(), err)
+			}
+		}
+	}
+	// and what fails to run must not parse
+	for _, text := range []string{"font comic"} {
+		if _, err := parser.Parse(text, idle.config.DefaultMode); err == nil {
+			t.Errorf("%q: expected a parse error", text)
+		}
+	}
+	for _, v := range parser.Verbs() {
+		if !covered[v] {
+			t.Errorf("no command exercises %s", v)
+		}
+	}
+}
+
+func TestStatusCommand(t *testing.T) {
+	m := send(t, New(config.Default(), audio.Silent{}), commandMsg("set auto 1:00,30 x2"), commandMsg("status"))
+	if m.message != "ready 1:00 1/2 R1/2" {
+		t.Errorf("expected status message, got %q", m.message)
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.message != "" {
+		t.Errorf("expected a key to dismiss the message, got %q", m.message)
+	}
+}
+
+func TestHelpCommand(t *testing.T) {
+	m := send(t, New(config.Default(), audio.Silent{}), tea.WindowSizeMsg{Width: 80, Height: 40})
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("?")})
+	if !m.showHelp || !strings.Contains(m.View(), "prompt") {
+		t.Fatalf("expected ? to show the help screen, got %q", m.View())
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("p")})
+	if m.showHelp || m.AppState() != Unconfigured {
+		t.Errorf("expected a key to close help without acting, got state %v", m.AppState())
+	}
+}
+
+func TestChainsAndMacros(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	cfg := config.Default()
+	cfg.Macros = map[string]string{"warmup": "set auto 5:00 x1; start"}
+	cfg.Keybindings["w"] = "warmup"
+	cfg.Keybindings["r"] = "reset; start"
+
+	m := send(t, New(cfg, audio.Silent{}), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("w")})
+	if m.AppState() != Running || m.prog.IntervalLength() != 5*time.Minute {
+		t.Fatalf("expected the warmup macro to start 5:00, got state %v", m.AppState())
+	}
+	m = send(t, m, commandMsg("pause"), tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("r")})
+	if m.AppState() != Running {
+		t.Errorf("expected a chained binding to reset and start, got state %v", m.AppState())
+	}
+
+	m = send(t, m, keys(":"), keys("clear; warmup"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.prompt.Open || m.AppState() != Running {
+		t.Errorf("expected the prompt to run the chain, got state %v", m.AppState())
+	}
+
+	m = send(t, m, keys(":"), keys("mute; font comic; unmute"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.muted || m.prompt.Error == "" {
+		t.Errorf("expected nothing in a chain that fails to parse to run, got muted %v error %q", m.muted, m.prompt.Error)
+	}
+}
+
+func TestKeybindingsAreChecked(t *testing.T) {
+	cfg := config.Default()
+	cfg.Keybindings["s"] = "set 5:00; start;"
+	cfg.Keybindings["x"] = "strat"
+
+	m := New(cfg, audio.Silent{})
+	if want := `keybinding x: unknown command: "strat" (did you mean "start"?)`; m.warning != want {
+		t.Errorf("expected %q, got %q", want, m.warning)
+	}
+	m = send(t, m, keys("s"))
+	if m.AppState() != Running {
+		t.Errorf("expected a trailing ; to be ignored, got state %v", m.AppState())
+	}
+}
+
+func TestMacroWarnings(t *testing.T) {
+	cfg := config.Default()
+	cfg.Macros = map[string]string{"a": "b", "b": "a"}
+	m := New(cfg, audio.Silent{})
+	if !strings.Contains(m.warning, `calls itself (a → b → a)`) {
+		t.Errorf("expected a recursion warning, got %q", m.warning)
+	}
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")}, keys("a"))
+	if !strings.Contains(m.prompt.Hint, "calls itself") || m.prompt.problem.Start != 0 || m.prompt.problem.End != 1 {
+		t.Errorf("expected the call underlined, got %q", m.prompt.Hint)
+	}
+}
@@ -0,0 +1,125 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+// cuePriority lists events from most to least significant. Only the most
+// significant event of an Update that has a cue gets it, so an
+// auto-advance plays the interval-start cue instead of a zero beep stacked
+// on top of it, or the zero cue when interval starts are silenced.
+var cuePriority = []EventKind{EventDone, EventRoundComplete, EventIntervalStart, EventZero, EventCountdown, EventLowTime, EventHalfway}
+
+// loadCues builds the configured cues. A cue is either a tone pattern or
+// a .wav/.ogg file, relative to dir unless absolute. An invalid pattern or
+// unreadable file falls back to the plain beep, and the problems are
+// returned as a warning for the view rather than failing startup.
+func loadCues(patterns map[string]string, dir string) (map[EventKind]audio.Sound, string) {
+	cues := make(map[EventKind]audio.Sound)
+	var problems []string
+	for name, pattern := range patterns {
+		kind, ok := ParseEventKind(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown cue %q", name))
+			continue
+		}
+		if pattern == "" {
+			continue // silenced
+		}
+		if audio.IsSoundFile(pattern) {
+			path := pattern
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, path)
+			}
+			sound, err := audio.LoadFile(name, path)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("cue %s: %v", name, err))
+				sound = audio.Beep
+			}
+			cues[kind] = sound
+			continue
+		}
+		tones, err := audio.ParsePattern(pattern)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("cue %s: %v", name, err))
+			cues[kind] = audio.Beep
+			continue
+		}
+		cues[kind] = audio.Synthesize(name, tones)
+	}
+	if len(problems) == 0 {
+		return cues, ""
+	}
+	sort.Strings(problems)
+	return cues, strings.Join(problems, "; ") + " (using the default beep)"
+}
+
+// newMixer puts a mixer configured with the volume, gains and overlap
+// policy in front of player. An invalid policy falls back to mixing.
+func newMixer(cfg config.Config, player audio.Player) (*audio.Mixer, string) {
+	overlap, err := audio.ParseOverlap(cfg.AudioOverlap)
+	var warning string
+	if err != nil {
+		warning = err.Error()
+	}
+	mixer := audio.NewMixer(player, overlap)
+	mixer.SetVolume(cfg.Volume)
+	for name, gain := range cfg.Gains {
+		mixer.SetGain(name, gain)
+	}
+	return mixer, warning
+}
+
+// joinWarnings combines startup warnings, skipping empty ones.
+func joinWarnings(warnings ...string) string {
+	var parts []string
+	for _, w := range warnings {
+		if w != "" {
+			parts = append(parts, w)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// pipLength is the duration of a countdown pip.
+const pipLength = 100 * time.Millisecond
+
+// countdownCue is the pip played for EventCountdown unless [cues] sets
+// its own pattern.
+func countdownCue(pitch int) audio.Sound {
+	return audio.Synthesize("countdown", []audio.Tone{{Freq: float64(pitch), Duration: pipLength}})
+}
+
+// playCue plays the cue for the most significant pending event that has
+// one. Muted, it plays nothing; during quiet hours the digits flash
+// instead.
+func (m Model) playCue() Model {
+	for _, kind := range cuePriority {
+		cue, ok := m.cues[kind]
+		if !ok {
+			continue
+		}
+		for _, e := range m.pending {
+			if e.Kind != kind {
+				continue
+			}
+			switch {
+			case m.muted:
+			case m.quiet():
+				m.flashUntil = m.now().Add(cmp.Or(m.flashDuration(), quietFlash))
+			default:
+				m.mixer.Play(cue)
+			}
+			return m
+		}
+	}
+	return m
+}
@@ -0,0 +1,171 @@
+package model
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// EventKind identifies something that happened during a workout.
+type EventKind int
+
+const (
+	// EventIntervalStart fires when the program starts and whenever it moves
+	// to a different interval (next, back, auto-advance, a new round).
+	EventIntervalStart EventKind = iota
+	// EventZero fires when the current interval's countdown reaches zero.
+	EventZero
+	// EventDone fires when the last interval of the last round completes.
+	EventDone
+	// EventRoundComplete fires when a round finishes and the next begins;
+	// an EventIntervalStart for the new round follows it.
+	EventRoundComplete
+	// EventLowTime fires when the countdown drops below the low-time
+	// warning threshold.
+	EventLowTime
+	// EventCountdown fires at each of the last few whole seconds before an
+	// interval reaches zero (3, 2, 1 by default).
+	EventCountdown
+	// EventHalfway fires when half of the current interval has elapsed.
+	EventHalfway
+)
+
+var eventKinds = []EventKind{EventIntervalStart, EventZero, EventDone, EventRoundComplete, EventLowTime, EventCountdown, EventHalfway}
+
+func (k EventKind) String() string {
+	switch k {
+	case EventIntervalStart:
+		return "interval_start"
+	case EventZero:
+		return "zero"
+	case EventDone:
+		return "done"
+	case EventRoundComplete:
+		return "round_complete"
+	case EventLowTime:
+		return "low_time"
+	case EventCountdown:
+		return "countdown"
+	case EventHalfway:
+		return "halfway"
+	}
+	return "unknown"
+}
+
+// ParseEventKind is the inverse of EventKind.String.
+func ParseEventKind(name string) (EventKind, bool) {
+	for _, k := range eventKinds {
+		if k.String() == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+// MarshalText lets events be written as JSON with readable kinds.
+func (k EventKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// Event is handed to event listeners after the Update that caused it.
+type Event struct {
+	Kind     EventKind `json:"event"`
+	Interval int       `json:"interval"` // 1-based, also set for single-interval programs
+	Round    int       `json:"round"`    // 1-based, keeps counting when looping forever
+	Snapshot Snapshot  `json:"snapshot"`
+}
+
+// position is the part of the model's state that event detection compares
+// before and after each Update.
+type position struct {
+	state           AppState
+	interval, round int
+	lowTime         bool
+	halfway         bool
+}
+
+func (m Model) position() position {
+	p := position{state: m.AppState()}
+	if m.prog != nil {
+		p.interval, p.round = m.prog.Position()
+		p.lowTime = m.prog.IsLowTime(m.lowTimeThreshold())
+		p.halfway = m.prog.IsHalfway()
+	}
+	return p
+}
+
+func (p position) active() bool {
+	return p.state == Running || p.state == Paused
+}
+
+// OnEvent registers f to receive every Event. Listeners run on the
+// Bubbletea goroutine and must not block.
+func (m Model) OnEvent(f func(Event)) Model {
+	m.listeners = append(m.listeners, f)
+	return m
+}
+
+// emit queues an event of the given kind at the current position; queued
+// events are delivered at the end of Update.
+func (m Model) emit(kind EventKind) Model {
+	var interval, round int
+	if m.prog != nil {
+		interval, round = m.prog.Position()
+	}
+	return m.emitAt(kind, interval, round)
+}
+
+// emitAt queues an event for an explicit zero-based position, for events
+// about an interval the program has already moved past.
+func (m Model) emitAt(kind EventKind, interval, round int) Model {
+	return m.emitSnapshot(kind, interval, round, m.Snapshot())
+}
+
+// emitSnapshot is emitAt with a snapshot taken before the program moved
+// on, so it still shows the interval the event is about.
+func (m Model) emitSnapshot(kind EventKind, interval, round int, s Snapshot) Model {
+	m.pending = append(m.pending, Event{
+		Kind:     kind,
+		Interval: interval + 1,
+		Round:    round + 1,
+		Snapshot: s,
+	})
+	return m
+}
+
+// emitTransitions queues the events implied by moving from before to the
+// current position.
+func (m Model) emitTransitions(before position) Model {
+	after := m.position()
+	moved := after.interval != before.interval || after.round != before.round
+	switch {
+	case after.state == Done && before.state != Done:
+		// A finished timer rewinds to the start; report where it finished.
+		m = m.emitAt(EventDone, before.interval, before.round)
+	case after.active() && (!before.active() || moved):
+		if before.active() && after.round == before.round+1 && after.interval == 0 {
+			m = m.emitAt(EventRoundComplete, before.interval, before.round)
+		}
+		m = m.emit(EventIntervalStart)
+	case after.state == Running && !moved:
+		if after.halfway && !before.halfway {
+			m = m.emit(EventHalfway)
+		}
+		if after.lowTime && !before.lowTime {
+			m = m.emit(EventLowTime)
+		}
+	}
+	return m
+}
+
+// flushEvents plays the cue for the most significant queued event, raises
+// the interval-end alert, speaks the announcements and delivers every
+// event to the listeners. It returns the command writing the alert.
+func (m Model) flushEvents() (Model, tea.Cmd) {
+	m = m.playCue()
+	m, alert := m.alert()
+	m.speak()
+	for _, e := range m.pending {
+		for _, f := range m.listeners {
+			f(e)
+		}
+	}
+	m.pending = nil
+	return m, alert
+}
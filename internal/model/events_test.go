@@ -0,0 +1,190 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+)
+
+// recordEvents returns a model with a listener appending to the returned slice.
+func recordEvents() (Model, *[]Event) {
+	var got []Event
+	m := New(config.Default(), audio.Silent{}).OnEvent(func(e Event) { got = append(got, e) })
+	return m, &got
+}
+
+// commandMsg parses command as the listeners do before sending it.
+func commandMsg(command string) CommandMsg {
+	chain, err := parser.ParseChain(command, config.Default().DefaultMode, nil)
+	if err != nil {
+		panic(err)
+	}
+	return CommandMsg(chain)
+}
+
+func send(t *testing.T, m Model, msgs ...any) Model {
+	t.Helper()
+	for _, msg := range msgs {
+		next, _ := m.Update(msg)
+		m = next.(Model)
+	}
+	return m
+}
+
+func kinds(events []Event) []EventKind {
+	out := make([]EventKind, len(events))
+	for i, e := range events {
+		out[i] = e.Kind
+	}
+	return out
+}
+
+func equalKinds(a, b []EventKind) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestEventsThroughAutoProgram(t *testing.T) {
+	m, got := recordEvents()
+	start := time.Now()
+	m = send(t, m,
+		commandMsg("set auto 10,5 x2"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(11*time.Second)), // interval 1 → 2
+		tickMsg(start.Add(17*time.Second)), // interval 2 → round 2
+	)
+
+	want := []EventKind{
+		EventIntervalStart,
+		EventZero, EventIntervalStart,
+		EventZero, EventRoundComplete, EventIntervalStart,
+	}
+	if !equalKinds(kinds(*got), want) {
+		t.Fatalf("got %v, want %v", kinds(*got), want)
+	}
+
+	// Zero and round-complete events describe the interval that just ended.
+	if e := (*got)[3]; e.Interval != 2 || e.Round != 1 {
+		t.Errorf("zero event at interval %d round %d, want 2/1", e.Interval, e.Round)
+	}
+	if e := (*got)[4]; e.Interval != 2 || e.Round != 1 {
+		t.Errorf("round complete at interval %d round %d, want 2/1", e.Interval, e.Round)
+	}
+	if e := (*got)[5]; e.Interval != 1 || e.Round != 2 {
+		t.Errorf("interval start at interval %d round %d, want 1/2", e.Interval, e.Round)
+	}
+}
+
+func TestEventsForManualCommands(t *testing.T) {
+	m, got := recordEvents()
+	m = send(t, m,
+		commandMsg("set manual 10,10"),
+		commandMsg("pause"), // starts from Ready
+		commandMsg("pause"), // pausing is not a transition
+		commandMsg("pause"),
+		commandMsg("next"),
+		commandMsg("back"),
+		commandMsg("reset"),
+	)
+
+	want := []EventKind{EventIntervalStart, EventIntervalStart, EventIntervalStart}
+	if !equalKinds(kinds(*got), want) {
+		t.Errorf("got %v, want %v", kinds(*got), want)
+	}
+}
+
+func TestDoneEvent(t *testing.T) {
+	m, got := recordEvents()
+	m = send(t, m,
+		commandMsg("set manual 10,10 x2"),
+		commandMsg("start"),
+		commandMsg("next"),
+		commandMsg("next"),
+		commandMsg("next"),
+		commandMsg("next"),
+	)
+
+	want := []EventKind{
+		EventIntervalStart, EventIntervalStart,
+		EventRoundComplete, EventIntervalStart, EventIntervalStart,
+		EventDone,
+	}
+	if !equalKinds(kinds(*got), want) {
+		t.Fatalf("got %v, want %v", kinds(*got), want)
+	}
+	if e := (*got)[5]; e.Interval != 2 || e.Round != 2 {
+		t.Errorf("done event at interval %d round %d, want 2/2", e.Interval, e.Round)
+	}
+}
+
+func TestLowTimeEvent(t *testing.T) {
+	m, got := recordEvents()
+	start := time.Now()
+	m = send(t, m,
+		commandMsg("set manual 40"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(5*time.Second)),
+		tickMsg(start.Add(11*time.Second)), // 29s left
+		tickMsg(start.Add(12*time.Second)),
+	)
+
+	want := []EventKind{EventIntervalStart, EventLowTime}
+	if !equalKinds(kinds(*got), want) {
+		t.Errorf("got %v, want %v", kinds(*got), want)
+	}
+}
+
+func TestEventsCarryLabel(t *testing.T) {
+	m, got := recordEvents()
+	start := time.Now()
+	m = send(t, m,
+		commandMsg("set auto work=10,rest=5 x1"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(11*time.Second)),
+	)
+
+	var labels []string
+	for _, e := range *got {
+		if e.Kind == EventIntervalStart {
+			labels = append(labels, e.Snapshot.Label)
+		}
+	}
+	if want := []string{"work", "rest"}; !equalStrings(labels, want) {
+		t.Errorf("expected interval start labels %q, got %q", want, labels)
+	}
+}
+
+func TestZeroEventDescribesFinishedInterval(t *testing.T) {
+	m, got := recordEvents()
+	start := time.Now()
+	m = send(t, m,
+		commandMsg("set auto work=10,rest=5 x1"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(11*time.Second)), // auto advances to rest
+	)
+
+	for _, e := range *got {
+		if e.Kind != EventZero {
+			continue
+		}
+		if e.Snapshot.Label != "work" || e.Snapshot.Time != "0:00" {
+			t.Errorf("expected the zero event to show work at 0:00, got %q at %s", e.Snapshot.Label, e.Snapshot.Time)
+		}
+		return
+	}
+	t.Errorf("expected a zero event, got %v", kinds(*got))
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+)
+
+// helpWidth caps the width of the command list on the help screen.
+const helpWidth = 60
+
+// renderHelp lists the keybindings, as configured, and the commands.
+func (m Model) renderHelp() string {
+	keys := make([]string, 0, len(m.config.Keybindings))
+	width := 0
+	for key := range m.config.Keybindings {
+		keys = append(keys, key)
+		width = max(width, len(key))
+	}
+	sort.Strings(keys)
+
+	lines := []string{m.paint(labelStyle).Render("Keys"), ""}
+	for _, key := range keys {
+		lines = append(lines, m.paint(lipgloss.NewStyle()).Render(fmt.Sprintf("%-*s  %s", width, key, m.config.Keybindings[key])))
+	}
+
+	var verbs []string
+	for _, v := range parser.Verbs() {
+		verbs = append(verbs, v.String())
+	}
+	wrap := helpWidth
+	if m.width > 0 {
+		wrap = min(wrap, m.width)
+	}
+	commands := m.paint(lipgloss.NewStyle().Width(wrap)).Render(strings.Join(verbs, " "))
+	lines = append(lines, "", m.paint(labelStyle).Render("Commands"), "", commands,
+		"", m.paint(hintStyle).Render("Press any key to close"))
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// history is the prompt's command history, oldest first. It is saved one
+// command per line so it survives restarts.
+type history struct {
+	entries []string
+	path    string // "" when not saved
+	size    int    // entries kept
+	pos     int    // entry shown while browsing; len(entries) when not
+	draft   string // what was typed before browsing began
+}
+
+// loadHistory reads the history saved at path. A missing file is an empty
+// history; with size 0 there is no history at all.
+func loadHistory(path string, size int) (history, error) {
+	if size <= 0 {
+		return history{}, nil
+	}
+	h := history{path: path, size: size}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.entries = h.entries[max(0, len(h.entries)-size):]
+	h.pos = len(h.entries)
+	return h, scanner.Err()
+}
+
+// add appends command, skipping a repeat of the last one, and saves the
+// history.
+func (h history) add(command string) (history, error) {
+	h.pos = len(h.entries)
+	h.draft = ""
+	if h.size <= 0 || command == "" || len(h.entries) > 0 && h.entries[len(h.entries)-1] == command {
+		return h, nil
+	}
+	// copy rather than append, since earlier Models share the slice
+	entries := make([]string, 0, len(h.entries)+1)
+	entries = append(entries, h.entries[max(0, len(h.entries)+1-h.size):]...)
+	h.entries = append(entries, command)
+	h.pos = len(h.entries)
+	return h, h.save()
+}
+
+func (h history) save() error {
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+}
+
+// prev steps back to the previous entry. current is what the prompt
+// holds, kept as the draft when browsing starts.
+func (h history) prev(current string) (history, string, bool) {
+	if h.pos == 0 {
+		return h, current, false
+	}
+	if h.pos == len(h.entries) {
+		h.draft = current
+	}
+	h.pos--
+	return h, h.entries[h.pos], true
+}
+
+// next steps forward, back to the draft after the newest entry.
+func (h history) next() (history, string, bool) {
+	if h.pos >= len(h.entries) {
+		return h, "", false
+	}
+	h.pos++
+	if h.pos == len(h.entries) {
+		return h, h.draft, true
+	}
+	return h, h.entries[h.pos], true
+}
+
+// search returns the newest entry before index from that contains query.
+func (h history) search(query string, from int) (int, bool) {
+	for i := min(from, len(h.entries)) - 1; i >= 0; i-- {
+		if strings.Contains(h.entries[i], query) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// sets returns the arguments of the `set` commands in the history, other
+// than chains, newest first, for completion.
+func (h history) sets() []string {
+	var out []string
+	for i := len(h.entries) - 1; i >= 0; i-- {
+		if args, ok := strings.CutPrefix(h.entries[i], "set "); ok && !strings.Contains(args, ";") {
+			out = append(out, args)
+		}
+	}
+	return out
+}
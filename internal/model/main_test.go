@@ -0,0 +1,20 @@
+package model
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain points the state directory at a scratch one, so a test that
+// opens the prompt without isolating its own history never writes to the
+// user's. Tests that depend on the history set their own.
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "workout-timer-state")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_STATE_HOME", dir)
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
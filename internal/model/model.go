@@ -1,13 +1,22 @@
 package model
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/BobbyGerace/workout-timer/internal/audio"
 	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
 	prog "github.com/BobbyGerace/workout-timer/internal/program"
+	"github.com/BobbyGerace/workout-timer/internal/renderer"
 )
 
 type AppState int
@@ -21,21 +30,45 @@ const (
 )
 
 type Prompt struct {
-	Input textinput.Model
-	Error string
-	Open  bool
+	Input   textinput.Model
+	Error   string
+	Hint    string // inline validation or completions
+	Open    bool
+	problem *parser.Error // located parse error, underlined in the input
+	history history
+	search  historySearch
 }
 
 type tickMsg time.Time
 
+// CommandMsg carries a parsed command chain from an external source (HTTP
+// API, FIFO) into Update, where it is executed exactly like a keybinding.
+type CommandMsg parser.Chain
+
 type Model struct {
 	width, height int
 	prog          prog.Program // nil when Unconfigured
 	lastTick      time.Time
 	prompt        Prompt
-	showHelp      bool          // (M19)
+	showHelp      bool          // help screen over the timer
 	config        config.Config // (M18)
+	mixer         *audio.Mixer
+	cues          map[EventKind]audio.Sound
+	say           func(text string) // nil unless speech is configured
+	phrases       map[EventKind]string
+	muted         bool
+	quietHours    quietHours
+	flashUntil    time.Time // digits flash until then
+	font          *renderer.Font
+	theme         Theme
+	urgency       Urgency
+	urgencyOut    io.Writer
+	warning       string // shown until the next keypress
+	message       string // reply to a command such as status, likewise
 	completionMsg string
+	observers     []func(Snapshot)
+	listeners     []func(Event)
+	pending       []Event // emitted during the current Update
 }
 
 func (m Model) AppState() AppState {
@@ -55,17 +88,93 @@ func (m Model) AppState() AppState {
 	return Unconfigured
 }
 
-func New() Model {
+func New(cfg config.Config, player audio.Player) Model {
 	input := textinput.New()
 	input.Placeholder = "set 1:30"
 	input.CharLimit = 100
 
+	mixer, mixerWarning := newMixer(cfg, player)
+	cues, warning := loadCues(cfg.Cues, config.Dir())
+	if _, ok := cfg.Cues["countdown"]; !ok {
+		cues[EventCountdown] = countdownCue(cfg.CountdownPitch)
+	}
+	phrases, phraseWarning := loadPhrases(cfg.Speech.Phrases)
+	quiet, err := parseQuietHours(cfg.QuietHours)
+	var quietWarning string
+	if err != nil {
+		quietWarning = err.Error()
+	}
+	urgency, err := ParseUrgency(cfg.Urgency)
+	var urgencyWarning string
+	if err != nil {
+		urgencyWarning = err.Error()
+	}
+	font, fontWarning := loadFont(cfg.Font)
+	theme, themeWarning := loadTheme(cfg.Theme, cfg.Colors, cfg.LabelColors)
+	historySize := cfg.HistorySize
+	if config.StateDir() == "" {
+		historySize = 0 // no home directory to keep it in
+	}
+	history, err := loadHistory(filepath.Join(config.StateDir(), "history"), historySize)
+	var historyWarning string
+	if err != nil {
+		historyWarning = fmt.Sprintf("reading history: %v", err)
+	}
+	var macroWarning string
+	if err := parser.CheckMacros(cfg.Macros, cfg.DefaultMode); err != nil {
+		macroWarning = err.Error()
+	}
+	warning = joinWarnings(mixerWarning, warning, phraseWarning, quietWarning, urgencyWarning, fontWarning, themeWarning, historyWarning, macroWarning, checkKeybindings(cfg))
+	var say func(string)
+	if cfg.Speech.Command != "" {
+		say = audio.NewSpeaker(cfg.Speech.Command, mixer).Say
+	}
+
 	return Model{
-		config: config.Default(),
-		prompt: Prompt{Input: input},
+		config:     cfg,
+		mixer:      mixer,
+		cues:       cues,
+		say:        say,
+		phrases:    phrases,
+		muted:      !cfg.Beep,
+		quietHours: quiet,
+		font:       font,
+		theme:      theme,
+		urgency:    urgency,
+		// stderr reaches the terminal without going through the renderer
+		urgencyOut: os.Stderr,
+		warning:    warning,
+		prompt:     Prompt{Input: input, history: history},
+	}
+}
+
+// checkKeybindings reports bindings whose commands don't parse, since
+// pressing them would otherwise do nothing.
+func checkKeybindings(cfg config.Config) string {
+	var problems []string
+	for key, command := range cfg.Keybindings {
+		if _, err := parser.ParseChain(command, cfg.DefaultMode, cfg.Macros); err != nil {
+			problems = append(problems, fmt.Sprintf("keybinding %s: %v", key, err))
+		}
 	}
+	sort.Strings(problems)
+	return strings.Join(problems, "; ")
+}
+
+// Observe registers f to receive a Snapshot after every Update. Observers run
+// on the Bubbletea goroutine and must not block.
+func (m Model) Observe(f func(Snapshot)) Model {
+	m.observers = append(m.observers, f)
+	return m
+}
+
+// Warn adds msg to the warning shown at startup, e.g. for a listener that
+// failed to start.
+func (m Model) Warn(msg string) Model {
+	m.warning = joinWarnings(m.warning, msg)
+	return m
 }
 
 func (m Model) Init() tea.Cmd {
-	return tick()
+	return tick(baseTick)
 }
@@ -0,0 +1,76 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// quietFlash is how long the digits flash in place of a silenced cue when
+// flash_duration turns the interval-end flash off.
+const quietFlash = time.Second
+
+// quietHours is a daily window, in minutes after midnight, during which
+// cues flash instead of playing. A window may wrap past midnight.
+type quietHours struct {
+	start, end int
+	set        bool
+}
+
+// parseQuietHours parses "HH:MM-HH:MM"; "" means no quiet hours.
+func parseQuietHours(s string) (quietHours, error) {
+	if s == "" {
+		return quietHours{}, nil
+	}
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return quietHours{}, fmt.Errorf("invalid quiet_hours %q: expected HH:MM-HH:MM", s)
+	}
+	start, err := parseClock(from)
+	if err != nil {
+		return quietHours{}, fmt.Errorf("invalid quiet_hours %q: %v", s, err)
+	}
+	end, err := parseClock(to)
+	if err != nil {
+		return quietHours{}, fmt.Errorf("invalid quiet_hours %q: %v", s, err)
+	}
+	return quietHours{start: start, end: end, set: true}, nil
+}
+
+// parseClock converts "HH:MM" to minutes after midnight.
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("bad time %q", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// contains reports whether t's local time of day falls in the window.
+func (q quietHours) contains(t time.Time) bool {
+	if !q.set {
+		return false
+	}
+	minute := t.Hour()*60 + t.Minute()
+	if q.start <= q.end {
+		return minute >= q.start && minute < q.end
+	}
+	return minute >= q.start || minute < q.end
+}
+
+// now is the model's idea of the current time: the latest tick, so that
+// tests can control it.
+func (m Model) now() time.Time {
+	if m.lastTick.IsZero() {
+		return time.Now()
+	}
+	return m.lastTick
+}
+
+func (m Model) quiet() bool {
+	return m.quietHours.contains(m.now())
+}
+
+func (m Model) flashing() bool {
+	return m.now().Before(m.flashUntil)
+}
@@ -0,0 +1,110 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, 1, 1, hour, minute, 0, 0, time.Local)
+	}
+	tests := []struct {
+		input   string
+		wantErr bool
+		quiet   []time.Time
+		loud    []time.Time
+	}{
+		{"", false, nil, []time.Time{at(3, 0)}},
+		{"12:00-13:30", false, []time.Time{at(12, 0), at(13, 29)}, []time.Time{at(11, 59), at(13, 30)}},
+		{"22:00-07:00", false, []time.Time{at(22, 0), at(3, 0), at(6, 59)}, []time.Time{at(7, 0), at(21, 59)}},
+		{"22:00", true, nil, nil},
+		{"25:00-07:00", true, nil, nil},
+		{"22:00-late", true, nil, nil},
+	}
+	for _, tt := range tests {
+		q, err := parseQuietHours(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got nil", tt.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		for _, when := range tt.quiet {
+			if !q.contains(when) {
+				t.Errorf("%q: expected %s to be quiet", tt.input, when.Format("15:04"))
+			}
+		}
+		for _, when := range tt.loud {
+			if q.contains(when) {
+				t.Errorf("%q: expected %s not to be quiet", tt.input, when.Format("15:04"))
+			}
+		}
+	}
+}
+
+func TestMuteCommands(t *testing.T) {
+	var rec audio.Recorder
+	m := New(config.Default(), &rec)
+	start := time.Now()
+	m = send(t, m,
+		commandMsg("set manual 10,10"),
+		tickMsg(start),
+		commandMsg("mute"),
+		commandMsg("start"),
+	)
+	if got := rec.Played(); len(got) != 0 {
+		t.Errorf("expected no cues while muted, got %v", got)
+	}
+	if m.audioIndicator() != "muted" {
+		t.Errorf("expected muted indicator, got %q", m.audioIndicator())
+	}
+
+	send(t, m, commandMsg("unmute"), commandMsg("next"))
+	if got := rec.Played(); len(got) != 1 || got[0] != "interval_start" {
+		t.Errorf("expected a cue after unmuting, got %v", got)
+	}
+}
+
+func TestBeepOffStartsMuted(t *testing.T) {
+	cfg := config.Default()
+	cfg.Beep = false
+	if m := New(cfg, audio.Silent{}); !m.muted {
+		t.Error("expected beep = false to start muted")
+	}
+}
+
+func TestQuietHoursFlashInstead(t *testing.T) {
+	cfg := config.Default()
+	cfg.QuietHours = "22:00-07:00"
+	var rec audio.Recorder
+	m := New(cfg, &rec)
+	night := time.Date(2024, 1, 1, 23, 0, 0, 0, time.Local)
+	m = send(t, m,
+		commandMsg("set 10"),
+		tickMsg(night),
+		commandMsg("start"),
+	)
+
+	if got := rec.Played(); len(got) != 0 {
+		t.Errorf("expected no cues in quiet hours, got %v", got)
+	}
+	if !m.flashing() {
+		t.Error("expected the digits to flash")
+	}
+	if m.audioIndicator() != "quiet hours" {
+		t.Errorf("expected quiet hours indicator, got %q", m.audioIndicator())
+	}
+
+	m = send(t, m, tickMsg(night.Add(2*time.Second)))
+	if m.flashing() {
+		t.Error("expected the flash to have ended")
+	}
+}
@@ -0,0 +1,70 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	prog "github.com/BobbyGerace/workout-timer/internal/program"
+)
+
+// Phase is whether the current interval is effort or recovery.
+type Phase int
+
+const (
+	PhaseWork Phase = iota
+	PhaseRest
+)
+
+// minBarWidth keeps progress bars readable under plain-text digits.
+const minBarWidth = 20
+
+// phase reports intervals labelled "rest", and the count-up after zero in
+// manual mode, as rest; everything else is work.
+func (m Model) phase() Phase {
+	if m.prog.IsOverflow() || strings.EqualFold(m.prog.Label(), "rest") {
+		return PhaseRest
+	}
+	return PhaseWork
+}
+
+// phaseColor is the interval's label colour, or else the theme's work or
+// rest colour.
+func (m Model) phaseColor() lipgloss.TerminalColor {
+	if c, ok := m.labelColor(); ok && !m.prog.IsOverflow() {
+		return c
+	}
+	if m.phase() == PhaseRest {
+		return m.theme.Rest
+	}
+	return m.theme.Work
+}
+
+// progressBars returns how far through the current interval and the whole
+// workout we are, as fractions. The workout bar is left out when it would
+// repeat the interval bar or the workout never ends.
+func (m Model) progressBars() []float64 {
+	length := m.prog.IntervalLength()
+	if length == 0 {
+		return nil
+	}
+	done := m.prog.State() == prog.ProgramDone
+	bars := []float64{1}
+	if !done && !m.prog.IsOverflow() {
+		bars[0] = 1 - float64(m.prog.TimeDisplayAt(time.Millisecond))/float64(length)
+	}
+	if total := m.prog.TotalDuration(); total > length {
+		bars = append(bars, 1-float64(m.prog.TotalRemaining())/float64(total))
+	}
+	return bars
+}
+
+// renderBar draws a bar width columns wide, filled to fraction in the
+// phase colour.
+func (m Model) renderBar(fraction float64, width int) string {
+	filled := int(math.Round(max(0, min(1, fraction)) * float64(width)))
+	return m.paint(lipgloss.NewStyle().Foreground(m.phaseColor())).Render(strings.Repeat("█", filled)) +
+		m.paint(hintStyle).Render(strings.Repeat("░", width-filled))
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestProgressBars(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	start := time.Now()
+	m = send(t, m, commandMsg("set auto work=45,rest=15 x2"), tickMsg(start), commandMsg("start"))
+
+	steps := []struct {
+		at    time.Duration
+		want  []float64
+		phase Phase
+	}{
+		{0, []float64{0, 0}, PhaseWork},
+		{15 * time.Second, []float64{1.0 / 3, 0.125}, PhaseWork},
+		{45 * time.Second, []float64{0, 45.0 / 120}, PhaseRest},
+		{50 * time.Second, []float64{1.0 / 3, 50.0 / 120}, PhaseRest},
+		{60 * time.Second, []float64{0, 0.5}, PhaseWork},
+		{105 * time.Second, []float64{0, 105.0 / 120}, PhaseRest},
+		{2 * time.Minute, []float64{1, 1}, PhaseWork}, // done
+	}
+	for _, step := range steps {
+		m = send(t, m, tickMsg(start.Add(step.at)))
+		got := m.progressBars()
+		if len(got) != len(step.want) {
+			t.Fatalf("at %v: expected %d bars, got %v", step.at, len(step.want), got)
+		}
+		for i := range got {
+			if math.Abs(got[i]-step.want[i]) > 1e-9 {
+				t.Errorf("at %v: bar %d expected %.3f, got %.3f", step.at, i, step.want[i], got[i])
+			}
+		}
+		if p := m.phase(); p != step.phase {
+			t.Errorf("at %v: expected phase %d, got %d", step.at, step.phase, p)
+		}
+	}
+}
+
+func TestProgressBarsPerProgram(t *testing.T) {
+	tests := []struct {
+		command string
+		bars    int
+	}{
+		{"set 60", 1},    // looping forever: no workout bar
+		{"set 60 x1", 1}, // the workout is the interval
+		{"set 60 x3", 2},
+		{"stopwatch", 0},
+	}
+	for _, tt := range tests {
+		m := send(t, New(config.Default(), audio.Silent{}), commandMsg(tt.command))
+		if got := m.progressBars(); len(got) != tt.bars {
+			t.Errorf("%s: expected %d bars, got %v", tt.command, tt.bars, got)
+		}
+	}
+}
+
+func TestManualOverflowIsRest(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	start := time.Now()
+	m = send(t, m, commandMsg("set manual 10"), tickMsg(start), commandMsg("start"), tickMsg(start.Add(11*time.Second)))
+	if p := m.phase(); p != PhaseRest {
+		t.Errorf("expected the count-up to be rest, got %d", p)
+	}
+	if bars := m.progressBars(); bars[0] != 1 {
+		t.Errorf("expected a full interval bar during overflow, got %v", bars)
+	}
+}
+
+func TestBarsDroppedBeforeLabels(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	m = send(t, m, commandMsg("set 45,15 x2"))
+	m.width = 80
+
+	m.height = 40
+	if view := m.View(); strings.Count(view, "░") == 0 || !strings.Contains(view, "Round 1/2") {
+		t.Errorf("expected labels and bars in a tall pane, got:\n%s", view)
+	}
+	m.height = 10
+	if view := m.View(); strings.Contains(view, "░") || !strings.Contains(view, "Round 1/2") {
+		t.Errorf("expected the bars to go first in a short pane, got:\n%s", view)
+	}
+}
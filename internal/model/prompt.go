@@ -0,0 +1,209 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/renderer"
+)
+
+// historySearch is the state of a Ctrl-R reverse search through the
+// history.
+type historySearch struct {
+	active bool
+	query  string
+	match  int    // index of the entry found
+	found  bool   // whether query matched anything
+	saved  string // the input before the search, restored on cancel
+}
+
+// openPrompt focuses the textinput and returns the blink command.
+func (m Model) openPrompt() (Model, tea.Cmd) {
+	m.prompt.Open = true
+	m.prompt.Error = ""
+	m.prompt.Hint = ""
+	m.prompt.problem = nil
+	m.prompt.Input.SetValue("")
+	m.prompt.Input.Focus()
+	m.prompt.history.pos = len(m.prompt.history.entries)
+	return m, textinput.Blink
+}
+
+func (m Model) closePrompt() Model {
+	m.prompt.Open = false
+	m.prompt.Error = ""
+	m.prompt.Hint = ""
+	m.prompt.problem = nil
+	m.prompt.Input.SetValue("")
+	return m
+}
+
+func (m Model) handlePromptKey(msg tea.KeyMsg) (Model, tea.Cmd) {
+	if m.prompt.search.active {
+		var done bool
+		if m, done = m.handleSearchKey(msg); done {
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	switch msg.String() {
+	case "esc":
+		m = m.closePrompt()
+	case "enter":
+		input := m.prompt.Input.Value()
+		if strings.TrimSpace(input) == "" {
+			return m.closePrompt(), nil
+		}
+		var err error
+		m, cmd, err = m.runCommand(input)
+		if err != nil {
+			m.prompt.Error, m.prompt.Hint = err.Error(), ""
+			return m, nil
+		}
+		var saveErr error
+		m.prompt.history, saveErr = m.prompt.history.add(strings.TrimSpace(input))
+		if saveErr != nil {
+			m.warning = fmt.Sprintf("saving history: %v", saveErr)
+		}
+		m = m.closePrompt()
+	case "up", "down":
+		var line string
+		var ok bool
+		if msg.String() == "up" {
+			m.prompt.history, line, ok = m.prompt.history.prev(m.prompt.Input.Value())
+		} else {
+			m.prompt.history, line, ok = m.prompt.history.next()
+		}
+		if ok {
+			m = m.setInput(line)
+		}
+	case "ctrl+r":
+		m.prompt.search = historySearch{active: true, saved: m.prompt.Input.Value()}
+		m = m.runSearch(len(m.prompt.history.entries))
+	case "tab":
+		m = m.complete()
+	default:
+		m.prompt.Input, cmd = m.prompt.Input.Update(msg)
+		m = m.validate()
+	}
+	return m, cmd
+}
+
+// handleSearchKey edits the search query. Keys it doesn't handle end the
+// search, leaving the match in the input, and report false so the caller
+// handles them too; Enter thus runs the match.
+func (m Model) handleSearchKey(msg tea.KeyMsg) (Model, bool) {
+	s := &m.prompt.search
+	switch msg.Type {
+	case tea.KeyRunes, tea.KeySpace:
+		s.query += string(msg.Runes)
+		return m.runSearch(len(m.prompt.history.entries)), true
+	case tea.KeyBackspace:
+		if q := []rune(s.query); len(q) > 0 {
+			s.query = string(q[:len(q)-1])
+		}
+		return m.runSearch(len(m.prompt.history.entries)), true
+	case tea.KeyCtrlR:
+		from := len(m.prompt.history.entries)
+		if s.found {
+			from = s.match
+		}
+		return m.runSearch(from), true
+	case tea.KeyEsc, tea.KeyCtrlG:
+		saved := s.saved
+		m.prompt.search = historySearch{}
+		return m.setInput(saved), true
+	}
+	m.prompt.search = historySearch{}
+	return m, false
+}
+
+// runSearch looks for the query in entries older than from. When a new
+// query matches nothing the search is failing; when Ctrl-R finds nothing
+// older the current match stays.
+func (m Model) runSearch(from int) Model {
+	s := &m.prompt.search
+	if i, ok := m.prompt.history.search(s.query, from); ok {
+		s.match, s.found = i, true
+		return m.setInput(m.prompt.history.entries[i])
+	}
+	if from == len(m.prompt.history.entries) {
+		s.found = false
+	}
+	return m
+}
+
+// complete extends the input to the longest prefix shared by its
+// completions, listing them when there is more than one.
+func (m Model) complete() Model {
+	lines := parser.Complete(m.prompt.Input.Value(), m.completionWords())
+	switch len(lines) {
+	case 0:
+		return m
+	case 1:
+		m = m.setInput(lines[0] + " ")
+		return m
+	}
+	m = m.setInput(parser.CommonPrefix(lines))
+	m.prompt.Hint = strings.Join(lines, "  ")
+	m.prompt.problem = nil
+	return m
+}
+
+// completionWords supplies the run-time names for completion: macros,
+// fonts, and workouts from the keybindings and the history.
+func (m Model) completionWords() map[string][]string {
+	sets := m.prompt.history.sets()
+	for _, command := range m.config.Keybindings {
+		if args, ok := strings.CutPrefix(command, "set "); ok && !strings.Contains(args, ";") {
+			sets = append(sets, args)
+		}
+	}
+	macros := make([]string, 0, len(m.config.Macros))
+	for name := range m.config.Macros {
+		macros = append(macros, name)
+	}
+	return map[string][]string{
+		"":     macros,
+		"font": renderer.Fonts(),
+		"set":  sets,
+	}
+}
+
+func (m Model) setInput(s string) Model {
+	m.prompt.Input.SetValue(s)
+	m.prompt.Input.CursorEnd()
+	return m.validate()
+}
+
+// validate checks the input as it is typed, leaving any problem as a hint
+// with its location.
+func (m Model) validate() Model {
+	m.prompt.Error = ""
+	m.prompt.Hint = ""
+	m.prompt.problem = nil
+	if input := m.prompt.Input.Value(); strings.TrimSpace(input) != "" {
+		if _, err := parser.ParseChain(input, m.config.DefaultMode, m.config.Macros); err != nil {
+			m.prompt.Hint = err.Error()
+			errors.As(err, &m.prompt.problem)
+		}
+	}
+	return m
+}
+
+// underline marks the problem's span beneath the input, a caret where
+// something is missing.
+func (m Model) underline() string {
+	value := m.prompt.Input.Value()
+	start := min(m.prompt.problem.Start, len(value))
+	end := min(max(m.prompt.problem.End, start), len(value))
+	col := lipgloss.Width(m.prompt.Input.Prompt) + lipgloss.Width(value[:start])
+	return strings.Repeat(" ", col) + strings.Repeat("^", max(1, lipgloss.Width(value[start:end])))
+}
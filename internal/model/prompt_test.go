@@ -0,0 +1,190 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func keys(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+// run opens the prompt, types line and presses Enter.
+func run(t *testing.T, m Model, line string) Model {
+	t.Helper()
+	return send(t, m, keys(":"), keys(line), tea.KeyMsg{Type: tea.KeyEnter})
+}
+
+func TestHistoryPersists(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := New(config.Default(), audio.Silent{})
+	m = run(t, m, "set 1:00")
+	m = run(t, m, "font square")
+	m = run(t, m, "font square")
+
+	data, err := os.ReadFile(filepath.Join(config.StateDir(), "history"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); got != "set 1:00\nfont square\n" {
+		t.Errorf("expected repeats to be skipped, got %q", got)
+	}
+
+	m = New(config.Default(), audio.Silent{})
+	m = send(t, m, keys(":"), keys("res"), tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.prompt.Input.Value(); got != "font square" {
+		t.Errorf("expected newest entry, got %q", got)
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyUp}, tea.KeyMsg{Type: tea.KeyUp})
+	if got := m.prompt.Input.Value(); got != "set 1:00" {
+		t.Errorf("expected oldest entry to stay, got %q", got)
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown})
+	if got := m.prompt.Input.Value(); got != "res" {
+		t.Errorf("expected the draft back, got %q", got)
+	}
+}
+
+func TestHistorySize(t *testing.T) {
+	dir := t.TempDir()
+	h, _ := loadHistory(filepath.Join(dir, "history"), 2)
+	for _, cmd := range []string{"pause", "resume", "next"} {
+		var err error
+		if h, err = h.add(cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h, _ = loadHistory(filepath.Join(dir, "history"), 2)
+	if !equalStrings(h.entries, []string{"resume", "next"}) {
+		t.Errorf("expected the newest two entries, got %q", h.entries)
+	}
+
+	t.Setenv("XDG_STATE_HOME", dir)
+	cfg := config.Default()
+	cfg.HistorySize = 0
+	m := run(t, New(cfg, audio.Silent{}), "pause")
+	if len(m.prompt.history.entries) != 0 {
+		t.Errorf("expected no history, got %q", m.prompt.history.entries)
+	}
+}
+
+func TestHistoryWithoutHome(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("XDG_STATE_HOME", "")
+	t.Setenv("HOME", "")
+
+	m := run(t, New(config.Default(), audio.Silent{}), "pause")
+	if len(m.prompt.history.entries) != 0 {
+		t.Errorf("expected no history, got %q", m.prompt.history.entries)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "history")); err == nil {
+		t.Error("expected no history file in the working directory")
+	}
+}
+
+func TestReverseSearch(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := New(config.Default(), audio.Silent{})
+	for _, line := range []string{"set auto 1:00 x3", "font square", "set 5:00"} {
+		m = run(t, m, line)
+	}
+
+	m = send(t, m, keys(":"), tea.KeyMsg{Type: tea.KeyCtrlR}, keys("set"))
+	if got := m.prompt.Input.Value(); got != "set 5:00" {
+		t.Errorf("expected newest match, got %q", got)
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyCtrlR})
+	if got := m.prompt.Input.Value(); got != "set auto 1:00 x3" {
+		t.Errorf("expected older match, got %q", got)
+	}
+	if view := strings.Join(m.renderPrompt(), "\n"); !strings.Contains(view, "(reverse-i-search)`set'") {
+		t.Errorf("expected search prompt, got %q", view)
+	}
+
+	m = send(t, m, keys("z"))
+	if view := strings.Join(m.renderPrompt(), "\n"); !strings.Contains(view, "failing reverse-i-search") {
+		t.Errorf("expected failing search, got %q", view)
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc})
+	if m.prompt.search.active || m.prompt.Input.Value() != "" || !m.prompt.Open {
+		t.Errorf("expected cancel to restore the input, got %q", m.prompt.Input.Value())
+	}
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyCtrlR}, keys("font"), tea.KeyMsg{Type: tea.KeyEnter})
+	if h := m.prompt.history.entries; m.prompt.Open || h[len(h)-1] != "font square" {
+		t.Errorf("expected Enter to run the match, got history %q", h)
+	}
+}
+
+func TestTabCompletion(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := New(config.Default(), audio.Silent{})
+
+	m = send(t, m, keys(":"), keys("pa"), tea.KeyMsg{Type: tea.KeyTab})
+	if got := m.prompt.Input.Value(); got != "pause " {
+		t.Errorf("expected single completion, got %q", got)
+	}
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc}, keys(":"), keys("st"), tea.KeyMsg{Type: tea.KeyTab})
+	if got := m.prompt.Input.Value(); got != "st" {
+		t.Errorf("expected input kept, got %q", got)
+	}
+	if m.prompt.Hint != "start  status  stopwatch" {
+		t.Errorf("expected candidates listed, got %q", m.prompt.Hint)
+	}
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc}, keys(":"), keys("set a"), tea.KeyMsg{Type: tea.KeyTab})
+	if got := m.prompt.Input.Value(); got != "set auto " {
+		t.Errorf("expected mode completion, got %q", got)
+	}
+}
+
+func TestInlineValidation(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := New(config.Default(), audio.Silent{})
+
+	m = send(t, m, keys(":"), keys("nxt"))
+	if m.prompt.Hint == "" || m.prompt.Error != "" {
+		t.Errorf("expected a hint for an unknown command, got hint %q error %q", m.prompt.Hint, m.prompt.Error)
+	}
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, keys("ext"))
+	if m.prompt.Hint != "" {
+		t.Errorf("expected no hint for a valid command, got %q", m.prompt.Hint)
+	}
+}
+
+func TestPromptUnderlinesProblem(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	m := New(config.Default(), audio.Silent{})
+
+	m = send(t, m, keys(":"), keys("set 1:60 x3"))
+	lines := m.renderPrompt()
+	if len(lines) != 3 {
+		t.Fatalf("expected input, underline and hint, got %q", lines)
+	}
+	if got := lines[1]; got != "        ^^" {
+		t.Errorf("expected the seconds underlined, got %q", got)
+	}
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyEsc}, keys(":"), keys("nxt"), tea.KeyMsg{Type: tea.KeyEnter})
+	lines = m.renderPrompt()
+	if got := lines[1]; got != "  ^^^" {
+		t.Errorf("expected the verb underlined, got %q", got)
+	}
+	if !strings.Contains(m.prompt.Error, `did you mean "next"?`) {
+		t.Errorf("expected a suggestion, got %q", m.prompt.Error)
+	}
+
+	m = send(t, m, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, keys("ext"))
+	if lines := m.renderPrompt(); len(lines) != 1 {
+		t.Errorf("expected no underline for a valid command, got %q", lines)
+	}
+}
@@ -0,0 +1,65 @@
+package model
+
+// Snapshot is a plain-data copy of what the view is showing, handed to
+// observers outside the Bubbletea program (e.g. the HTTP API). It is
+// comparable so observers can cheaply skip unchanged states.
+type Snapshot struct {
+	State     string `json:"state"`
+	Time      string `json:"time"`
+	Label     string `json:"label"`
+	Interval  int    `json:"interval"`
+	Intervals int    `json:"intervals"`
+	Round     int    `json:"round"`
+	Rounds    int    `json:"rounds"`
+	LowTime   bool   `json:"low_time"`
+	Overflow  bool   `json:"overflow"`
+}
+
+func (s AppState) String() string {
+	switch s {
+	case Ready:
+		return "ready"
+	case Running:
+		return "running"
+	case Paused:
+		return "paused"
+	case Done:
+		return "done"
+	}
+	return "unconfigured"
+}
+
+// Snapshot captures the current display state.
+func (m Model) Snapshot() Snapshot {
+	s := Snapshot{State: m.AppState().String()}
+	if m.prog == nil {
+		return s
+	}
+	s.Time = formatTime(m.prog.TimeDisplay())
+	s.Interval, s.Intervals = m.prog.IntervalProgress()
+	s.Round, s.Rounds = m.prog.RoundProgress()
+	s.LowTime = m.prog.IsLowTime(m.lowTimeThreshold())
+	s.Overflow = m.prog.IsOverflow()
+	switch m.AppState() {
+	case Running:
+		s.Label = m.prog.Label()
+	case Done:
+		s.Label = m.completionMsg
+	case Paused:
+		s.Label = "PAUSED"
+	case Ready:
+		s.Label = "Ready"
+	}
+	return s
+}
+
+// notify hands the current snapshot to every registered observer.
+func (m Model) notify() {
+	if len(m.observers) == 0 {
+		return
+	}
+	s := m.Snapshot()
+	for _, f := range m.observers {
+		f(s)
+	}
+}
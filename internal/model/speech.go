@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadPhrases maps the configured phrase templates to event kinds,
+// returning a warning for names that aren't events.
+func loadPhrases(templates map[string]string) (map[EventKind]string, string) {
+	phrases := make(map[EventKind]string)
+	var problems []string
+	for name, template := range templates {
+		kind, ok := ParseEventKind(name)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown phrase %q", name))
+			continue
+		}
+		if template != "" {
+			phrases[kind] = template
+		}
+	}
+	sort.Strings(problems)
+	return phrases, strings.Join(problems, "; ")
+}
+
+// formatPhrase expands a phrase template. It takes the status line
+// placeholders plus #{duration}, the time left spoken in words, but
+// #{interval} and #{round} come from the event, so a round_complete phrase
+// names the round that just finished. Spaces are collapsed, so an empty
+// placeholder such as #{label} on an unlabelled interval leaves no gap.
+func formatPhrase(template string, e Event, remaining time.Duration) string {
+	r := strings.NewReplacer(
+		"#{duration}", spokenDuration(remaining),
+		"#{interval}", strconv.Itoa(e.Interval),
+		"#{round}", strconv.Itoa(e.Round),
+	)
+	return strings.Join(strings.Fields(FormatStatus(r.Replace(template), e.Snapshot)), " ")
+}
+
+// spokenDuration writes d out for a TTS engine, e.g. "1 minute 30 seconds".
+func spokenDuration(d time.Duration) string {
+	total := int(d.Round(time.Second).Seconds())
+	units := []struct {
+		name string
+		size int
+	}{{"hour", 3600}, {"minute", 60}, {"second", 1}}
+	var parts []string
+	for _, u := range units {
+		n := total / u.size
+		total %= u.size
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+u.name)
+		case n > 1:
+			parts = append(parts, strconv.Itoa(n)+" "+u.name+"s")
+		}
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return strings.Join(parts, " ")
+}
+
+// speak announces the pending events that have a phrase, joined into one
+// utterance so they can't be spoken out of order.
+func (m Model) speak() {
+	if m.say == nil || m.muted || m.quiet() {
+		return
+	}
+	var remaining time.Duration
+	if m.prog != nil {
+		remaining = m.prog.TimeDisplay()
+	}
+	var sentences []string
+	for _, e := range m.pending {
+		if template, ok := m.phrases[e.Kind]; ok {
+			if sentence := formatPhrase(template, e, remaining); sentence != "" {
+				sentences = append(sentences, sentence)
+			}
+		}
+	}
+	if len(sentences) > 0 {
+		m.say(strings.Join(sentences, ". "))
+	}
+}
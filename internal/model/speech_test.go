@@ -0,0 +1,86 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestSpokenDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0 seconds"},
+		{time.Second, "1 second"},
+		{20 * time.Second, "20 seconds"},
+		{time.Minute, "1 minute"},
+		{90 * time.Second, "1 minute 30 seconds"},
+		{time.Hour + 2*time.Minute, "1 hour 2 minutes"},
+	}
+	for _, tt := range tests {
+		if got := spokenDuration(tt.d); got != tt.want {
+			t.Errorf("spokenDuration(%v): expected %q, got %q", tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestAnnouncements(t *testing.T) {
+	cfg := config.Default()
+	cfg.Speech.Phrases["zero"] = "Interval #{interval} done"
+	m := New(cfg, audio.Silent{})
+	var said []string
+	m.say = func(text string) { said = append(said, text) }
+
+	start := time.Now()
+	send(t, m,
+		commandMsg("set auto 20,10 x2"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(10*time.Second)), // halfway through the first interval
+		tickMsg(start.Add(21*time.Second)),
+		tickMsg(start.Add(31*time.Second)),
+	)
+
+	want := []string{
+		"20 seconds",
+		"Halfway",
+		"Interval 1 done. 10 seconds",
+		"Interval 2 done. Round 1 complete. 20 seconds",
+	}
+	if len(said) != len(want) {
+		t.Fatalf("expected %q, got %q", want, said)
+	}
+	for i := range want {
+		if said[i] != want[i] {
+			t.Errorf("announcement %d: expected %q, got %q", i, want[i], said[i])
+		}
+	}
+}
+
+func TestAnnouncementsWithLabels(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	var said []string
+	m.say = func(text string) { said = append(said, text) }
+
+	start := time.Now()
+	send(t, m,
+		commandMsg("set auto work=20,10 x1"),
+		tickMsg(start),
+		commandMsg("start"),
+		tickMsg(start.Add(21*time.Second)),
+	)
+
+	// The unlabelled second interval leaves no gap where #{label} was.
+	want := []string{"work 20 seconds", "10 seconds"}
+	if len(said) != len(want) {
+		t.Fatalf("expected %q, got %q", want, said)
+	}
+	for i := range want {
+		if said[i] != want[i] {
+			t.Errorf("announcement %d: expected %q, got %q", i, want[i], said[i])
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// DefaultStatusFormat is used by `timer status` and `timer watch --oneline`
+// when no --format is given.
+const DefaultStatusFormat = "#{label} #{remaining} #{progress}"
+
+// StatusColor selects how FormatStatusLine colours its output.
+type StatusColor int
+
+const (
+	StatusColorNone StatusColor = iota
+	StatusColorANSI             // SGR escape codes, for terminals
+	StatusColorTmux             // #[fg=...] markup, for status-left/right
+)
+
+// ParseStatusColor converts a --color flag value into a StatusColor.
+func ParseStatusColor(s string) (StatusColor, error) {
+	switch s {
+	case "none":
+		return StatusColorNone, nil
+	case "ansi":
+		return StatusColorANSI, nil
+	case "tmux":
+		return StatusColorTmux, nil
+	}
+	return 0, fmt.Errorf("invalid color mode %q: expected none, ansi or tmux", s)
+}
+
+// FormatStatus expands tmux-style placeholders in format:
+//
+//	#{state}      unconfigured, ready, running, paused or done
+//	#{remaining}  the time as displayed, e.g. 1:30
+//	#{label}      the label under the digits, e.g. PAUSED
+//	#{interval}   current interval (0 when there is only one)
+//	#{intervals}  total intervals (0 when there is only one)
+//	#{round}      current round (0 when not counted)
+//	#{rounds}     total rounds (0 when not counted)
+//	#{progress}   compact counters, e.g. "2/3 R1/5", empty when not applicable
+//
+// Runs of whitespace left by empty placeholders are collapsed.
+func FormatStatus(format string, s Snapshot) string {
+	r := strings.NewReplacer(
+		"#{state}", s.State,
+		"#{remaining}", s.Time,
+		"#{label}", s.Label,
+		"#{interval}", strconv.Itoa(s.Interval),
+		"#{intervals}", strconv.Itoa(s.Intervals),
+		"#{round}", strconv.Itoa(s.Round),
+		"#{rounds}", strconv.Itoa(s.Rounds),
+		"#{progress}", progressText(s),
+	)
+	return strings.Join(strings.Fields(r.Replace(format)), " ")
+}
+
+// FormatStatusLine is FormatStatus wrapped in the colour theme would draw
+// the big digits in.
+func FormatStatusLine(format string, s Snapshot, mode StatusColor, theme Theme) string {
+	line := FormatStatus(format, s)
+	var color lipgloss.TerminalColor
+	switch {
+	case s.LowTime:
+		color = theme.LowTime
+	case s.Overflow:
+		color = theme.Overflow
+	default:
+		return line
+	}
+
+	switch mode {
+	case StatusColorANSI:
+		return "\x1b[" + sgrForeground(statusColor(color)) + "m" + line + "\x1b[0m"
+	case StatusColorTmux:
+		return "#[fg=" + tmuxColor(statusColor(color)) + "]" + line + "#[default]"
+	}
+	return line
+}
+
+func progressText(s Snapshot) string {
+	var parts []string
+	if s.Intervals > 0 {
+		parts = append(parts, fmt.Sprintf("%d/%d", s.Interval, s.Intervals))
+	}
+	if s.Rounds > 0 {
+		parts = append(parts, fmt.Sprintf("R%d/%d", s.Round, s.Rounds))
+	}
+	return strings.Join(parts, " ")
+}
+
+// statusColor picks the dark side of an adaptive colour, since a status
+// line can't ask the terminal for its background.
+func statusColor(c lipgloss.TerminalColor) lipgloss.Color {
+	if a, ok := c.(lipgloss.AdaptiveColor); ok {
+		return lipgloss.Color(a.Dark)
+	}
+	color, _ := c.(lipgloss.Color)
+	return color
+}
+
+// tmuxColor returns c in tmux's notation: colourN or #rrggbb.
+func tmuxColor(c lipgloss.Color) string {
+	if r, g, b, ok := hexRGB(c); ok {
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	}
+	return "colour" + string(c)
+}
+
+// sgrForeground returns the SGR parameter for a 256-colour palette index
+// or a truecolor hex value.
+func sgrForeground(c lipgloss.Color) string {
+	if r, g, b, ok := hexRGB(c); ok {
+		return fmt.Sprintf("38;2;%d;%d;%d", r, g, b)
+	}
+	n, _ := strconv.Atoi(string(c))
+	switch {
+	case n < 8:
+		return strconv.Itoa(30 + n)
+	case n < 16:
+		return strconv.Itoa(90 + n - 8)
+	}
+	return "38;5;" + strconv.Itoa(n)
+}
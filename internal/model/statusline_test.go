@@ -0,0 +1,94 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestFormatStatus(t *testing.T) {
+	running := Snapshot{State: "running", Time: "1:30", Interval: 2, Intervals: 3, Round: 1, Rounds: 5}
+	paused := Snapshot{State: "paused", Time: "0:10", Label: "PAUSED"}
+
+	tests := []struct {
+		format string
+		snap   Snapshot
+		want   string
+	}{
+		{DefaultStatusFormat, running, "1:30 2/3 R1/5"},
+		{DefaultStatusFormat, paused, "PAUSED 0:10"},
+		{"#{label} #{remaining} #{interval}/#{intervals}", running, "1:30 2/3"},
+		{"#{state}: #{remaining} (round #{round} of #{rounds})", running, "running: 1:30 (round 1 of 5)"},
+		{"#{unknown}", running, "#{unknown}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := FormatStatus(tt.format, tt.snap); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatusShowsRunningLabel(t *testing.T) {
+	start := time.Now()
+	m := send(t, New(config.Default(), audio.Silent{}),
+		commandMsg("set auto work=45,rest=15 x2"),
+		tickMsg(start),
+		commandMsg("start"),
+	)
+	if got := FormatStatus("#{label} #{remaining}", m.Snapshot()); got != "work 0:45" {
+		t.Errorf("expected %q, got %q", "work 0:45", got)
+	}
+}
+
+func TestFormatStatusLineColors(t *testing.T) {
+	low := Snapshot{Time: "0:05", LowTime: true}
+	over := Snapshot{Time: "0:03", Overflow: true}
+
+	if got := FormatStatusLine("#{remaining}", low, StatusColorTmux, DarkTheme); got != "#[fg=colour3]0:05#[default]" {
+		t.Errorf("tmux low time: got %q", got)
+	}
+	if got := FormatStatusLine("#{remaining}", over, StatusColorANSI, DarkTheme); got != "\x1b[36m0:03\x1b[0m" {
+		t.Errorf("ansi overflow: got %q", got)
+	}
+	if got := FormatStatusLine("#{remaining}", low, StatusColorNone, DarkTheme); got != "0:05" {
+		t.Errorf("no colour: got %q", got)
+	}
+	if got := FormatStatusLine("#{remaining}", Snapshot{Time: "2:00"}, StatusColorTmux, DarkTheme); got != "2:00" {
+		t.Errorf("normal time should be uncoloured: got %q", got)
+	}
+}
+
+func TestFormatStatusLineThemeColors(t *testing.T) {
+	low := Snapshot{Time: "0:05", LowTime: true}
+	over := Snapshot{Time: "0:03", Overflow: true}
+	cfg := config.Default()
+	cfg.Theme = "high-contrast"
+	cfg.Colors = map[string]string{"overflow": "#0af"}
+	theme := ThemeFor(cfg)
+
+	tests := []struct {
+		name  string
+		snap  Snapshot
+		mode  StatusColor
+		theme Theme
+		want  string
+	}{
+		{"tmux hex", low, StatusColorTmux, theme, "#[fg=#ffff00]0:05#[default]"},
+		{"ansi hex", low, StatusColorANSI, theme, "\x1b[38;2;255;255;0m0:05\x1b[0m"},
+		{"configured short hex", over, StatusColorTmux, theme, "#[fg=#00aaff]0:03#[default]"},
+		{"adaptive uses dark", low, StatusColorTmux, AutoTheme, "#[fg=colour3]0:05#[default]"},
+		{"bright palette index", low, StatusColorANSI, Theme{LowTime: lipgloss.Color("11")}, "\x1b[93m0:05\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := FormatStatusLine("#{remaining}", tt.snap, tt.mode, tt.theme); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+// Theme is the set of colours the view draws with. A colour is an ANSI
+// palette index, a "#rrggbb" truecolor value, which lipgloss degrades to
+// whatever the terminal supports, or an adaptive pair chosen by the
+// terminal's background.
+type Theme struct {
+	Name     string
+	Timer    lipgloss.TerminalColor
+	LowTime  lipgloss.TerminalColor
+	Overflow lipgloss.TerminalColor
+	Done     lipgloss.TerminalColor
+	Ready    lipgloss.TerminalColor // background before the start
+	Error    lipgloss.TerminalColor
+	Work     lipgloss.TerminalColor
+	Rest     lipgloss.TerminalColor
+	// Labels colours intervals by label, overriding Work and Rest.
+	Labels map[string]lipgloss.TerminalColor
+}
+
+// DarkTheme is the default: the terminal's own palette, so it follows the
+// user's colour scheme.
+var DarkTheme = Theme{
+	Name:     "dark",
+	Timer:    lipgloss.Color("15"),
+	LowTime:  lipgloss.Color("3"),
+	Overflow: lipgloss.Color("6"),
+	Done:     lipgloss.Color("10"),
+	Ready:    lipgloss.Color("8"),
+	Error:    lipgloss.Color("9"),
+	Work:     lipgloss.Color("1"),
+	Rest:     lipgloss.Color("4"),
+}
+
+var LightTheme = Theme{
+	Name:     "light",
+	Timer:    lipgloss.Color("#1a1a1a"),
+	LowTime:  lipgloss.Color("#9a6700"),
+	Overflow: lipgloss.Color("#0e7490"),
+	Done:     lipgloss.Color("#1a7f37"),
+	Ready:    lipgloss.Color("#d1d5db"),
+	Error:    lipgloss.Color("#cf222e"),
+	Work:     lipgloss.Color("#c2410c"),
+	Rest:     lipgloss.Color("#1d4ed8"),
+}
+
+var HighContrastTheme = Theme{
+	Name:     "high-contrast",
+	Timer:    lipgloss.Color("#ffffff"),
+	LowTime:  lipgloss.Color("#ffff00"),
+	Overflow: lipgloss.Color("#00ffff"),
+	Done:     lipgloss.Color("#00ff00"),
+	Ready:    lipgloss.Color("#ffffff"),
+	Error:    lipgloss.Color("#ff0000"),
+	Work:     lipgloss.Color("#ff5f00"),
+	Rest:     lipgloss.Color("#00afff"),
+}
+
+// ColorblindTheme uses the Okabe–Ito palette, whose orange and blue stay
+// distinct under every common form of colour blindness.
+var ColorblindTheme = Theme{
+	Name:     "colorblind",
+	Timer:    lipgloss.Color("15"),
+	LowTime:  lipgloss.Color("#f0e442"),
+	Overflow: lipgloss.Color("#56b4e9"),
+	Done:     lipgloss.Color("#009e73"),
+	Ready:    lipgloss.Color("#cc79a7"),
+	Error:    lipgloss.Color("#d55e00"),
+	Work:     lipgloss.Color("#e69f00"),
+	Rest:     lipgloss.Color("#0072b2"),
+}
+
+// AutoTheme picks the light or dark colours to suit the terminal's
+// background.
+var AutoTheme = Theme{
+	Name:     "auto",
+	Timer:    adaptive(LightTheme.Timer, DarkTheme.Timer),
+	LowTime:  adaptive(LightTheme.LowTime, DarkTheme.LowTime),
+	Overflow: adaptive(LightTheme.Overflow, DarkTheme.Overflow),
+	Done:     adaptive(LightTheme.Done, DarkTheme.Done),
+	Ready:    adaptive(LightTheme.Ready, DarkTheme.Ready),
+	Error:    adaptive(LightTheme.Error, DarkTheme.Error),
+	Work:     adaptive(LightTheme.Work, DarkTheme.Work),
+	Rest:     adaptive(LightTheme.Rest, DarkTheme.Rest),
+}
+
+var themes = map[string]Theme{
+	DarkTheme.Name:         DarkTheme,
+	LightTheme.Name:        LightTheme,
+	HighContrastTheme.Name: HighContrastTheme,
+	ColorblindTheme.Name:   ColorblindTheme,
+	AutoTheme.Name:         AutoTheme,
+}
+
+func adaptive(light, dark lipgloss.TerminalColor) lipgloss.AdaptiveColor {
+	return lipgloss.AdaptiveColor{Light: string(light.(lipgloss.Color)), Dark: string(dark.(lipgloss.Color))}
+}
+
+// hexColor matches "#rgb" and "#rrggbb".
+var hexColor = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}){1,2}$`)
+
+// parseColor reads a palette index (0–255), a "#rrggbb" hex colour or a
+// "<light>/<dark>" pair of either.
+func parseColor(s string) (lipgloss.TerminalColor, error) {
+	if light, dark, ok := strings.Cut(s, "/"); ok {
+		l, err := parseColor(light)
+		if err != nil {
+			return nil, err
+		}
+		d, err := parseColor(dark)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := d.(lipgloss.Color); !ok {
+			return nil, fmt.Errorf("invalid color %q: pairs can't be nested", s)
+		}
+		return adaptive(l, d), nil
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return lipgloss.Color(s), nil
+	}
+	if hexColor.MatchString(s) {
+		return lipgloss.Color(s), nil
+	}
+	return nil, fmt.Errorf("invalid color %q: expected 0-255, #rrggbb or light/dark", s)
+}
+
+// ThemeFor returns the theme cfg configures, for output outside the TUI
+// such as the status line. Problems are ignored; the TUI reports them.
+func ThemeFor(cfg config.Config) Theme {
+	theme, _ := loadTheme(cfg.Theme, cfg.Colors, cfg.LabelColors)
+	return theme
+}
+
+// loadTheme returns the named theme with the configured colours applied on
+// top. Problems are reported as a warning and leave the theme's colour.
+func loadTheme(name string, colors, labelColors map[string]string) (Theme, string) {
+	var problems []string
+	theme, ok := themes[name]
+	if !ok {
+		problems = append(problems, unknownTheme(name).Error())
+		theme = DarkTheme
+	}
+
+	roles := map[string]*lipgloss.TerminalColor{
+		"timer":    &theme.Timer,
+		"low_time": &theme.LowTime,
+		"overflow": &theme.Overflow,
+		"done":     &theme.Done,
+		"ready":    &theme.Ready,
+		"error":    &theme.Error,
+		"work":     &theme.Work,
+		"rest":     &theme.Rest,
+	}
+	for role, value := range colors {
+		target, ok := roles[role]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("unknown color %q", role))
+			continue
+		}
+		c, err := parseColor(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("colors.%s: %v", role, err))
+			continue
+		}
+		*target = c
+	}
+
+	theme.Labels = map[string]lipgloss.TerminalColor{}
+	for label, value := range labelColors {
+		c, err := parseColor(value)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("label_colors.%s: %v", label, err))
+			continue
+		}
+		theme.Labels[label] = c
+	}
+	sort.Strings(problems)
+	return theme, strings.Join(problems, "; ")
+}
+
+func unknownTheme(name string) error {
+	names := make([]string, 0, len(themes))
+	for n := range themes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown theme %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// labelColor returns the colour for the current interval's label: one set
+// in label_colors, or the work or rest colour for intervals named that.
+func (m Model) labelColor() (lipgloss.TerminalColor, bool) {
+	label := m.prog.Label()
+	if c, ok := m.theme.Labels[label]; ok {
+		return c, true
+	}
+	switch strings.ToLower(label) {
+	case "work":
+		return m.theme.Work, true
+	case "rest":
+		return m.theme.Rest, true
+	}
+	return nil, false
+}
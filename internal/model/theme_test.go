@@ -0,0 +1,89 @@
+package model
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    lipgloss.TerminalColor
+		wantErr bool
+	}{
+		{"3", lipgloss.Color("3"), false},
+		{"255", lipgloss.Color("255"), false},
+		{"#e69f00", lipgloss.Color("#e69f00"), false},
+		{"#fff", lipgloss.Color("#fff"), false},
+		{"#1a1a1a/15", lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "15"}, false},
+		{"256", nil, true},
+		{"-1", nil, true},
+		{"red", nil, true},
+		{"#12345", nil, true},
+		{"1/2/3", nil, true},
+		{"", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseColor(tt.input)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%q: expected error, got %v", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%q: expected %v, got %v (%v)", tt.input, tt.want, got, err)
+		}
+	}
+}
+
+func TestLoadTheme(t *testing.T) {
+	theme, warning := loadTheme("colorblind", map[string]string{"timer": "#ffffff"}, map[string]string{"warmup": "3"})
+	if warning != "" {
+		t.Errorf("unexpected warning: %q", warning)
+	}
+	if theme.Timer != lipgloss.Color("#ffffff") || theme.Work != ColorblindTheme.Work {
+		t.Errorf("expected the override on top of the theme, got %v and %v", theme.Timer, theme.Work)
+	}
+	if theme.Labels["warmup"] != lipgloss.Color("3") {
+		t.Errorf("expected the warmup label colour, got %v", theme.Labels["warmup"])
+	}
+
+	theme, warning = loadTheme("neon", map[string]string{"work": "red", "glow": "1"}, nil)
+	if theme.Name != "dark" || theme.Work != DarkTheme.Work {
+		t.Errorf("expected the dark theme unchanged, got %s with work %v", theme.Name, theme.Work)
+	}
+	for _, want := range []string{`unknown theme "neon"`, `colors.work: invalid color "red"`, `unknown color "glow"`} {
+		if !strings.Contains(warning, want) {
+			t.Errorf("expected warning to contain %q, got %q", want, warning)
+		}
+	}
+}
+
+func TestLabelColors(t *testing.T) {
+	cfg := config.Default()
+	cfg.LabelColors = map[string]string{"warmup": "3"}
+	m := New(cfg, audio.Silent{})
+
+	tests := []struct {
+		command string
+		want    lipgloss.TerminalColor
+		ok      bool
+	}{
+		{"set warmup=60", lipgloss.Color("3"), true},
+		{"set work=45", DarkTheme.Work, true},
+		{"set REST=15", DarkTheme.Rest, true},
+		{"set 60", nil, false},
+	}
+	for _, tt := range tests {
+		m = send(t, m, commandMsg(tt.command))
+		if got, ok := m.labelColor(); got != tt.want || ok != tt.ok {
+			t.Errorf("%s: expected %v (%v), got %v (%v)", tt.command, tt.want, tt.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// timeline lists the intervals coming up and, for workouts with an end
+// beyond the current interval, the total time left, e.g.
+// "Next: rest 0:15 · work 0:45 · 6:30 left". Entries are dropped from the
+// far end until it fits the pane's width.
+func (m Model) timeline() string {
+	if m.config.Timeline <= 0 || m.AppState() == Done {
+		return ""
+	}
+	var entries []string
+	for _, next := range m.prog.Upcoming(m.config.Timeline) {
+		entry := formatTime(next.Duration)
+		if next.Label != "" {
+			entry = next.Label + " " + entry
+		}
+		entries = append(entries, entry)
+	}
+	var left string
+	if m.prog.TotalDuration() > m.prog.IntervalLength() {
+		left = formatTime(m.prog.TotalRemaining()) + " left"
+	}
+
+	for n := len(entries); n >= 0; n-- {
+		parts := entries[:n]
+		if left != "" {
+			parts = append(parts[:n:n], left)
+		}
+		if len(parts) == 0 {
+			return ""
+		}
+		line := strings.Join(parts, " · ")
+		if n > 0 {
+			line = "Next: " + line
+		}
+		if lipgloss.Width(line) <= m.width {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+)
+
+func TestTimeline(t *testing.T) {
+	tests := []struct {
+		command string
+		width   int
+		want    string
+	}{
+		{"set auto work=45,rest=15 x2", 80, "Next: rest 0:15 · work 0:45 · rest 0:15 · 2:00 left"},
+		{"set auto work=45,rest=15 x2", 30, "Next: rest 0:15 · 2:00 left"},
+		{"set auto work=45,rest=15 x2", 10, "2:00 left"},
+		{"set 1:30,60", 80, "Next: 1:00 · 1:30 · 1:00"},
+		{"set 60 x1", 80, ""},
+		{"stopwatch", 80, ""},
+	}
+	for _, tt := range tests {
+		m := send(t, New(config.Default(), audio.Silent{}), commandMsg(tt.command))
+		m.width = tt.width
+		if got := m.timeline(); got != tt.want {
+			t.Errorf("%s at width %d: expected %q, got %q", tt.command, tt.width, tt.want, got)
+		}
+	}
+
+	cfg := config.Default()
+	cfg.Timeline = 0
+	m := send(t, New(cfg, audio.Silent{}), commandMsg("set auto work=45,rest=15 x2"))
+	m.width = 80
+	if got := m.timeline(); got != "" {
+		t.Errorf("expected no timeline when it is hidden, got %q", got)
+	}
+}
+
+func TestTimelineDroppedFirst(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	start := time.Now()
+	m = send(t, m, commandMsg("set auto work=45,rest=15 x2"), tickMsg(start), commandMsg("start"))
+	m.width = 80
+
+	m.height = 40
+	if view := m.View(); !strings.Contains(view, "Next: rest 0:15") {
+		t.Errorf("expected the timeline in a tall pane, got:\n%s", view)
+	}
+	// Room for 10-row digits, the labels and the bars, but nothing more.
+	m.height = 2 + 10 + 4*2
+	view := m.View()
+	if strings.Contains(view, "Next:") || !strings.Contains(view, "░") {
+		t.Errorf("expected the timeline to go before the bars, got:\n%s", view)
+	}
+}
@@ -4,10 +4,9 @@ import (
 	"math/rand"
 	"time"
 
-	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
-	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
 	prog "github.com/BobbyGerace/workout-timer/internal/program"
 )
 
@@ -19,13 +18,40 @@ var completionMessages = []string{
 	"Workout complete!",
 }
 
-func tick() tea.Cmd {
-	return tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg {
+// baseTick is often enough for whole seconds, countdown pips and flashes.
+const baseTick = 100 * time.Millisecond
+
+func tick(d time.Duration) tea.Cmd {
+	return tea.Tick(d, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
+// tickRate returns how often to tick so the last digit shown changes
+// smoothly: every tenth is drawn at least once, and hundredths update at
+// the renderer's 60 fps frame rate.
+func (m Model) tickRate() time.Duration {
+	if m.prog == nil || m.prog.State() != prog.ProgramRunning {
+		return baseTick
+	}
+	switch m.precision() {
+	case 1:
+		return 50 * time.Millisecond
+	case 2:
+		return time.Second / 60
+	}
+	return baseTick
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	before := m.position()
+	m, cmd := m.update(msg)
+	m, alert := m.emitTransitions(before).flushEvents()
+	m.notify()
+	return m, tea.Batch(cmd, alert)
+}
+
+func (m Model) update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
@@ -34,11 +60,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.handleKey(msg)
 	case tickMsg:
 		return m.handleTick(msg)
+	case CommandMsg:
+		m, cmd, _ := m.executeChain(parser.Chain(msg))
+		return m, cmd
 	}
 	return m, nil
 }
 
-func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+func (m Model) handleKey(msg tea.KeyMsg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	// ctrl+c always quits, regardless of state
@@ -46,27 +75,18 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Quit
 	}
 
+	// any key dismisses a startup warning or a command's reply
+	m.warning = ""
+	m.message = ""
+
 	if m.prompt.Open {
-		switch msg.String() {
-		case "esc":
-			m.prompt.Open = false
-			m.prompt.Error = ""
-			m.prompt.Input.SetValue("")
-		case "enter":
-			var err error
-			m, cmd, err = m.executeCommand(m.prompt.Input.Value())
-			if err != nil {
-				m.prompt.Error = err.Error()
-				cmd = nil
-			} else {
-				m.prompt.Open = false
-				m.prompt.Error = ""
-				m.prompt.Input.SetValue("")
-			}
-		default:
-			m.prompt.Input, cmd = m.prompt.Input.Update(msg)
-		}
-		return m, cmd
+		return m.handlePromptKey(msg)
+	}
+
+	// any other key closes the help screen
+	if m.showHelp {
+		m.showHelp = false
+		return m, nil
 	}
 
 	key := msg.String()
@@ -74,33 +94,31 @@ func (m Model) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		key = "space"
 	}
 	if command, ok := m.config.Keybindings[key]; ok {
-		m, cmd, _ = m.executeCommand(command)
+		m, cmd, _ = m.runCommand(command)
 		return m, cmd
 	}
 
 	return m, nil
 }
 
-func (m Model) handleTick(msg tickMsg) (tea.Model, tea.Cmd) {
+func (m Model) handleTick(msg tickMsg) (Model, tea.Cmd) {
 	now := time.Time(msg)
 	if !m.lastTick.IsZero() && m.prog != nil && m.prog.State() == prog.ProgramRunning {
 		elapsed := now.Sub(m.lastTick)
+		interval, round := m.prog.Position()
+		// Auto mode moves on within Tick, so describe the interval first.
+		atZero := m.Snapshot()
+		atZero.Time = formatTime(0)
 		if m.prog.Tick(elapsed) {
-			audio.Beep()
+			m = m.emitSnapshot(EventZero, interval, round, atZero)
+		}
+		if s := m.prog.CrossedSecond(); s > 0 && s <= m.config.CountdownPips {
+			m = m.emit(EventCountdown)
 		}
 		if m.prog.State() == prog.ProgramDone && m.completionMsg == "" {
 			m.completionMsg = completionMessages[rand.Intn(len(completionMessages))]
 		}
 	}
 	m.lastTick = now
-	return m, tick()
-}
-
-// openPrompt focuses the textinput and returns the blink command.
-func (m Model) openPrompt() (Model, tea.Cmd) {
-	m.prompt.Open = true
-	m.prompt.Error = ""
-	m.prompt.Input.SetValue("")
-	m.prompt.Input.Focus()
-	return m, textinput.Blink
+	return m, tick(m.tickRate())
 }
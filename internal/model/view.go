@@ -8,37 +8,43 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/BobbyGerace/workout-timer/internal/renderer"
+	"github.com/BobbyGerace/workout-timer/internal/stopwatch"
 )
 
 var labelStyle = lipgloss.NewStyle().Faint(true)
 
-var timerStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("15"))
-
 var hintStyle = lipgloss.NewStyle().
 	Faint(true)
 
-var errorStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("9"))
-
-var lowTimeStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("3"))
-
-var overflowStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("6"))
-
 var pausedStyle = lipgloss.NewStyle().
 	Faint(true)
 
-var completionStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("10")).
-	Bold(true)
+func (m Model) errorStyle() lipgloss.Style {
+	return m.paint(lipgloss.NewStyle().Foreground(m.theme.Error))
+}
+
+func (m Model) completionStyle() lipgloss.Style {
+	return m.paint(lipgloss.NewStyle().Foreground(m.theme.Done).Bold(true))
+}
 
 func (m Model) View() string {
-	promptLines := m.renderPrompt()
-	promptHeight := len(promptLines)
+	bottomLines := m.renderPrompt()
+	if m.warning != "" {
+		bottomLines = append([]string{m.errorStyle().Render(m.warning)}, bottomLines...)
+	}
+	if m.message != "" {
+		bottomLines = append([]string{m.paint(hintStyle).Render(m.message)}, bottomLines...)
+	}
+	if indicator := m.audioIndicator(); indicator != "" {
+		bottomLines = append([]string{m.paint(hintStyle).Render(indicator)}, bottomLines...)
+	}
+	bottomHeight := len(bottomLines)
 
-	mainHeight := max(m.height-promptHeight, 0)
+	mainHeight := max(m.height-bottomHeight, 0)
+	var fill []lipgloss.WhitespaceOption
+	if bg := m.background(); bg != nil {
+		fill = append(fill, lipgloss.WithWhitespaceBackground(bg))
+	}
 
 	var mainContent string
 	switch m.AppState() {
@@ -47,73 +53,220 @@ func (m Model) View() string {
 		mainContent = lipgloss.Place(m.width, mainHeight, lipgloss.Center, lipgloss.Center, hint)
 	case Done:
 		content := m.renderTime(mainHeight)
-		content += "\n\n" + completionStyle.Render(m.completionMsg)
-		mainContent = lipgloss.Place(m.width, mainHeight, lipgloss.Center, lipgloss.Top, "\n"+content)
+		content += "\n\n" + m.completionStyle().Render(m.completionMsg)
+		mainContent = lipgloss.Place(m.width, mainHeight, lipgloss.Center, lipgloss.Top, "\n"+content, fill...)
 	default:
 		content := m.renderTime(mainHeight)
 		if m.AppState() == Paused {
-			content += "\n\n" + pausedStyle.Render("PAUSED")
+			content += "\n\n" + m.paint(pausedStyle).Render("PAUSED")
 		} else if m.AppState() == Ready {
-			content += "\n\n" + hintStyle.Render("Press space to start")
+			content += "\n\n" + m.paint(hintStyle).Render("Press space to start")
 		}
-		mainContent = lipgloss.Place(m.width, mainHeight, lipgloss.Center, lipgloss.Top, "\n"+content)
+		mainContent = lipgloss.Place(m.width, mainHeight, lipgloss.Center, lipgloss.Top, "\n"+content, fill...)
 	}
 
-	if promptHeight == 0 {
+	if m.showHelp {
+		mainContent = lipgloss.Place(m.width, mainHeight, lipgloss.Center, lipgloss.Center, m.renderHelp(), fill...)
+	}
+
+	if bottomHeight == 0 {
 		return mainContent
 	}
-	return mainContent + "\n" + strings.Join(promptLines, "\n")
+	return mainContent + "\n" + m.fill(strings.Join(bottomLines, "\n"))
 }
 
-// bigDigitHeight is the fixed row count of the big-digit font.
-const bigDigitHeight = 5
-
 func (m Model) renderTime(availableHeight int) string {
-	timeStr := formatTime(m.prog.TimeDisplay())
-	rows := renderer.BigDigits(timeStr)
+	timeStr := m.timeString()
+
+	// Labels in display priority order; each costs 1 row + 1 blank separator.
+	var labels []string
+	if sw, ok := m.prog.(*stopwatch.Stopwatch); ok {
+		if laps := sw.Laps(); len(laps) > 0 {
+			places := m.precision()
+			last := laps[len(laps)-1].Truncate(resolution(places))
+			labels = append(labels, fmt.Sprintf("Lap %d: %s", len(laps), formatTimePrecise(last, places)))
+		}
+	}
+	if cur, total := m.prog.IntervalProgress(); total > 0 {
+		labels = append(labels, fmt.Sprintf("Interval %d/%d", cur, total))
+	}
+	if cur, total := m.prog.RoundProgress(); total > 0 {
+		labels = append(labels, fmt.Sprintf("Round %d/%d", cur, total))
+	}
+
+	// Progress bars come after the labels and are dropped before them.
+	bars := m.progressBars()
+	items := len(labels) + len(bars)
+
+	// Pick the largest digits that leave room for the labels, dropping the
+	// lowest-priority label before falling back to plain text.
+	budget := availableHeight - 2 // -2 for the leading and trailing "\n" in Place
+	var rows []string
+	for n := items; n >= 0; n-- {
+		var size renderer.Size
+		var ok bool
+		rows, size, ok = renderer.Fit(timeStr, m.font, m.width, budget-2*n)
+		if ok && size > renderer.Rows1 || n == 0 {
+			break
+		}
+	}
 
-	style := timerStyle
-	timeIsLow := m.prog.IsLowTime(time.Duration(m.config.LowTimeWarning) * time.Second)
-	if timeIsLow {
-		style = lowTimeStyle
+	// Warnings win over the interval's own colour.
+	color := m.theme.Timer
+	if c, ok := m.labelColor(); ok {
+		color = c
+	}
+	if m.prog.IsLowTime(m.lowTimeThreshold()) {
+		color = m.theme.LowTime
 	} else if m.prog.IsOverflow() {
-		style = overflowStyle
+		color = m.theme.Overflow
 	}
+	style := m.paint(lipgloss.NewStyle().Foreground(color))
 
-	result := style.Render(strings.Join(rows, "\n")) + "\n"
+	if m.flashing() {
+		style = style.Reverse(true)
+	}
 
-	// Budget remaining lines for labels (each costs 1 row + 1 blank separator).
-	budgetLeft := availableHeight - bigDigitHeight - 2 // -2 for the leading and trailing  "\n" in Place
+	result := style.Render(strings.Join(rows, "\n")) + "\n"
 
-	intervalCur, intervalTotal := m.prog.IntervalProgress()
-	if intervalTotal > 0 && budgetLeft >= 2 {
-		result += "\n" + labelStyle.Render(fmt.Sprintf("Interval %d/%d", intervalCur, intervalTotal))
+	budgetLeft := budget - len(rows)
+	barWidth := min(max(renderer.Width(rows), minBarWidth), m.width)
+	var lines []string
+	for i := 0; i < items && budgetLeft >= 2; i++ {
+		if i < len(labels) {
+			lines = append(lines, m.paint(labelStyle).Render(labels[i]))
+		} else {
+			lines = append(lines, m.renderBar(bars[i-len(labels)], barWidth))
+		}
 		budgetLeft -= 2
 	}
 
-	roundCur, roundTotal := m.prog.RoundProgress()
-	if roundTotal > 0 && budgetLeft >= 2 {
-		result += "\n" + labelStyle.Render(fmt.Sprintf("Round %d/%d", roundCur, roundTotal))
+	// The timeline sits right under the digits but only takes room nothing
+	// else needs, so it is the first thing to go in a short pane.
+	if len(lines) == items && budgetLeft >= 2 {
+		if timeline := m.timeline(); timeline != "" {
+			lines = append([]string{m.paint(hintStyle).Render(timeline)}, lines...)
+		}
+	}
+	for _, line := range lines {
+		result += "\n" + line
 	}
 
 	return result
 }
 
+// loadFont returns the named font, falling back to the bevelled one with a
+// warning. User fonts are registered by the caller before New.
+func loadFont(name string) (*renderer.Font, string) {
+	font, ok := renderer.Lookup(name)
+	if !ok {
+		return renderer.Bevelled, unknownFont(name).Error()
+	}
+	return font, ""
+}
+
+func unknownFont(name string) error {
+	return fmt.Errorf("unknown font %q (available: %s)", name, strings.Join(renderer.Fonts(), ", "))
+}
+
+// audioIndicator explains why cues are silent, if they are.
+func (m Model) audioIndicator() string {
+	switch {
+	case m.muted:
+		return "muted"
+	case m.quiet():
+		return "quiet hours"
+	}
+	return ""
+}
+
+func (m Model) lowTimeThreshold() time.Duration {
+	return time.Duration(m.config.LowTimeWarning) * time.Second
+}
+
 func (m Model) renderPrompt() []string {
 	if !m.prompt.Open {
 		return nil
 	}
-	lines := []string{m.prompt.Input.View()}
+	input := m.prompt.Input.View()
+	if s := m.prompt.search; s.active {
+		label := "reverse-i-search"
+		if !s.found && s.query != "" {
+			label = "failing " + label
+		}
+		input = fmt.Sprintf("(%s)`%s': %s", label, s.query, m.prompt.Input.Value())
+	}
+	lines := []string{input}
+	if m.prompt.problem != nil && !m.prompt.search.active {
+		lines = append(lines, m.errorStyle().Render(m.underline()))
+	}
 	if m.prompt.Error != "" {
-		lines = append(lines, errorStyle.Render(m.prompt.Error))
+		lines = append(lines, m.errorStyle().Render(m.prompt.Error))
+	} else if m.prompt.Hint != "" {
+		lines = append(lines, m.paint(hintStyle).Render(m.prompt.Hint))
 	}
 	return lines
 }
 
-// formatTime formats a duration as M:SS.
+// finalSeconds is how close to zero a countdown switches to the final
+// precision.
+const finalSeconds = 10 * time.Second
+
+// precision returns the decimal places of a second to show for the current
+// program.
+func (m Model) precision() int {
+	p := m.config.Precision
+	if _, ok := m.prog.(*stopwatch.Stopwatch); ok {
+		return clampPlaces(p.Stopwatch)
+	}
+	places := clampPlaces(p.Timer)
+	final := clampPlaces(p.Final)
+	if final > places && !m.prog.IsOverflow() && m.prog.TimeDisplayAt(resolution(final)) < finalSeconds {
+		return final
+	}
+	return places
+}
+
+// timeString formats the program's time at its precision.
+func (m Model) timeString() string {
+	places := m.precision()
+	if places == 0 {
+		return formatTime(m.prog.TimeDisplay())
+	}
+	return formatTimePrecise(m.prog.TimeDisplayAt(resolution(places)), places)
+}
+
+func clampPlaces(n int) int {
+	return max(0, min(2, n))
+}
+
+// resolution returns the smallest step shown with the given decimal places.
+func resolution(places int) time.Duration {
+	r := time.Second
+	for range places {
+		r /= 10
+	}
+	return r
+}
+
+// formatTimePrecise is formatTime followed by places decimal digits of a
+// second, e.g. 0:05.37.
+func formatTimePrecise(d time.Duration, places int) string {
+	if places == 0 {
+		return formatTime(d)
+	}
+	frac := d % time.Second / resolution(places)
+	return fmt.Sprintf("%s.%0*d", formatTime(d), places, frac)
+}
+
+// formatTime formats a duration as M:SS, or H:MM:SS from an hour up.
 func formatTime(d time.Duration) string {
 	total := int(d.Seconds())
-	minutes := total / 60
+	hours := total / 3600
+	minutes := total / 60 % 60
 	seconds := total % 60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
 	return fmt.Sprintf("%d:%02d", minutes, seconds)
 }
@@ -0,0 +1,132 @@
+package model
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/BobbyGerace/workout-timer/internal/audio"
+	"github.com/BobbyGerace/workout-timer/internal/config"
+	"github.com/BobbyGerace/workout-timer/internal/renderer"
+)
+
+func TestFontCommand(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()) // no user fonts
+	m := New(config.Default(), audio.Silent{})
+	if m.font != renderer.Bevelled {
+		t.Fatalf("expected the bevelled font by default, got %s", m.font.Name)
+	}
+
+	m, _, err := m.runCommand("font square")
+	if err != nil || m.font != renderer.Square {
+		t.Errorf("expected the square font, got %s (%v)", m.font.Name, err)
+	}
+
+	_, _, err = m.runCommand("font comic")
+	if err == nil || !strings.Contains(err.Error(), `expected "bevelled", "powerline" or "square"`) {
+		t.Errorf("expected an error listing the fonts, got %v", err)
+	}
+}
+
+func TestUnknownConfiguredFont(t *testing.T) {
+	cfg := config.Default()
+	cfg.Font = "comic"
+	m := New(cfg, audio.Silent{})
+	if m.font != renderer.Bevelled || !strings.Contains(m.warning, `unknown font "comic"`) {
+		t.Errorf("expected a warning and the bevelled font, got %s, %q", m.font.Name, m.warning)
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{90 * time.Second, "1:30"},
+		{59*time.Minute + 59*time.Second, "59:59"},
+		{time.Hour, "1:00:00"},
+		{2*time.Hour + 5*time.Minute + 9*time.Second, "2:05:09"},
+	}
+	for _, tt := range tests {
+		if got := formatTime(tt.d); got != tt.want {
+			t.Errorf("formatTime(%v): expected %q, got %q", tt.d, tt.want, got)
+		}
+	}
+}
+
+func TestHourLongTimeFitsWidth(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	m = send(t, m, commandMsg("set 1:30:00"))
+	for _, width := range []int{20, 50, 80, 200} {
+		m.width, m.height = width, 40
+		for _, line := range strings.Split(m.View(), "\n") {
+			if w := lipgloss.Width(line); w > width {
+				t.Errorf("width %d: line is %d columns wide: %q", width, w, line)
+			}
+		}
+	}
+}
+
+func TestFormatTimePrecise(t *testing.T) {
+	tests := []struct {
+		d      time.Duration
+		places int
+		want   string
+	}{
+		{5370 * time.Millisecond, 0, "0:05"},
+		{5370 * time.Millisecond, 1, "0:05.3"},
+		{5370 * time.Millisecond, 2, "0:05.37"},
+		{time.Hour + 40*time.Millisecond, 2, "1:00:00.04"},
+	}
+	for _, tt := range tests {
+		if got := formatTimePrecise(tt.d, tt.places); got != tt.want {
+			t.Errorf("formatTimePrecise(%v, %d): expected %q, got %q", tt.d, tt.places, got, tt.want)
+		}
+	}
+}
+
+func TestFinalSecondsPrecision(t *testing.T) {
+	cfg := config.Default()
+	cfg.Precision.Final = 1
+	m := New(cfg, audio.Silent{})
+	start := time.Now()
+	m = send(t, m, commandMsg("set manual 0:12"), tickMsg(start), commandMsg("start"))
+
+	steps := []struct {
+		at   time.Duration
+		want string
+		rate time.Duration
+	}{
+		{1500 * time.Millisecond, "0:11", baseTick},
+		{2050 * time.Millisecond, "0:10", baseTick}, // 9.95s shows 10.0, still whole seconds
+		{2730 * time.Millisecond, "0:09.3", 50 * time.Millisecond},
+		{12500 * time.Millisecond, "0:00", baseTick}, // overflow counts up in whole seconds
+	}
+	for _, step := range steps {
+		m = send(t, m, tickMsg(start.Add(step.at)))
+		if got := m.timeString(); got != step.want {
+			t.Errorf("at %v: expected %q, got %q", step.at, step.want, got)
+		}
+		if got := m.tickRate(); got != step.rate {
+			t.Errorf("at %v: expected ticks every %v, got %v", step.at, step.rate, got)
+		}
+	}
+}
+
+func TestStopwatchShowsTenthsAndLaps(t *testing.T) {
+	m := New(config.Default(), audio.Silent{})
+	start := time.Now()
+	m = send(t, m, tickMsg(start), commandMsg("stopwatch"), tickMsg(start.Add(3460*time.Millisecond)))
+	if got := m.timeString(); got != "0:03.4" {
+		t.Errorf("expected 0:03.4, got %q", got)
+	}
+
+	m = send(t, m, commandMsg("next"))
+	m.width, m.height = 80, 24
+	if view := m.View(); !strings.Contains(view, "Lap 1: 0:03.4") {
+		t.Errorf("expected the lap time in the view, got:\n%s", view)
+	}
+}
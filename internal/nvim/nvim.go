@@ -0,0 +1,299 @@
+// Package nvim connects the timer to a running Neovim instance over
+// msgpack-RPC so a Lua plugin can display and drive it.
+//
+// On connect the client stores its channel id in g:workout_timer_channel
+// and fires `User WorkoutTimerAttach`. Every state change fires
+// `User WorkoutTimerState` with the snapshot as the autocmd's data. The
+// plugin sends commands back with either of:
+//
+//	vim.rpcnotify(vim.g.workout_timer_channel, "command", "next")
+//	vim.rpcrequest(vim.g.workout_timer_channel, "command", "set 1:30") -- errors are raised
+package nvim
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// msgpack-RPC message types.
+const (
+	rpcRequest      = 0
+	rpcResponse     = 1
+	rpcNotification = 2
+)
+
+// replyTimeout bounds how long Dial waits for Neovim to answer.
+const replyTimeout = 5 * time.Second
+
+type response struct {
+	err    any
+	result any
+}
+
+// Client is a msgpack-RPC connection to Neovim.
+type Client struct {
+	conn        net.Conn
+	dispatch    func(commands parser.Chain)
+	defaultMode types.Mode
+	macros      map[string]string
+
+	writeMu sync.Mutex
+	enc     *msgpack.Encoder
+
+	pendingMu sync.Mutex
+	nextID    uint32
+	pending   map[uint32]chan response
+
+	// Publish only records the latest snapshot and pokes the writer, so it
+	// never blocks the Bubbletea goroutine on the socket.
+	stateMu sync.Mutex
+	last    model.Snapshot
+	dirty   chan struct{}
+	done    chan struct{}
+}
+
+// Dial connects to the Neovim listening at address (a Unix socket path as
+// found in $NVIM, or host:port) and announces the channel. Valid commands
+// received from Neovim are passed to dispatch, which must be safe to call
+// from any goroutine.
+func Dial(address string, defaultMode types.Mode, macros map[string]string, dispatch func(commands parser.Chain)) (*Client, error) {
+	network := "unix"
+	if !strings.Contains(address, "/") && strings.Contains(address, ":") {
+		network = "tcp"
+	}
+	conn, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+
+	enc := msgpack.NewEncoder(conn)
+	enc.SetCustomStructTag("json")
+	c := &Client{
+		conn:        conn,
+		dispatch:    dispatch,
+		defaultMode: defaultMode,
+		macros:      macros,
+		enc:         enc,
+		pending:     make(map[uint32]chan response),
+		dirty:       make(chan struct{}, 1),
+		done:        make(chan struct{}),
+	}
+	go c.readLoop()
+
+	info, err := c.call("nvim_get_api_info")
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("nvim_get_api_info: %w", err)
+	}
+	parts, ok := info.([]any)
+	if !ok || len(parts) < 1 {
+		conn.Close()
+		return nil, errors.New("nvim_get_api_info: unexpected reply")
+	}
+	c.notify("nvim_set_var", "workout_timer_channel", parts[0])
+	c.fireUser("WorkoutTimerAttach", nil)
+
+	go c.writeLoop()
+	return c, nil
+}
+
+// Publish queues snap to be sent to Neovim. Unchanged snapshots are dropped
+// and only the most recent one is sent if several arrive at once.
+func (c *Client) Publish(snap model.Snapshot) {
+	c.stateMu.Lock()
+	defer c.stateMu.Unlock()
+	if snap == c.last {
+		return
+	}
+	c.last = snap
+	select {
+	case c.dirty <- struct{}{}:
+	default:
+	}
+}
+
+// Close disconnects from Neovim.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-c.dirty:
+			c.stateMu.Lock()
+			snap := c.last
+			c.stateMu.Unlock()
+			if c.fireUser("WorkoutTimerState", snap) != nil {
+				return
+			}
+		}
+	}
+}
+
+// fireUser triggers `User <pattern>` autocommands, passing data through to
+// the callback as ev.data.
+func (c *Client) fireUser(pattern string, data any) error {
+	opts := map[string]any{"pattern": pattern, "modeline": false}
+	if data != nil {
+		opts["data"] = data
+	}
+	return c.notify("nvim_exec_autocmds", "User", opts)
+}
+
+func (c *Client) send(msg ...any) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	return c.enc.Encode(msg)
+}
+
+func (c *Client) notify(method string, args ...any) error {
+	return c.send(rpcNotification, method, params(args))
+}
+
+// call sends a request and waits for its response.
+func (c *Client) call(method string, args ...any) (any, error) {
+	ch := make(chan response, 1)
+	c.pendingMu.Lock()
+	id := c.nextID
+	c.nextID++
+	c.pending[id] = ch
+	c.pendingMu.Unlock()
+
+	if err := c.send(rpcRequest, id, method, params(args)); err != nil {
+		return nil, err
+	}
+
+	select {
+	case r := <-ch:
+		if r.err != nil {
+			return nil, fmt.Errorf("%v", r.err)
+		}
+		return r.result, nil
+	case <-c.done:
+		return nil, errors.New("connection closed")
+	case <-time.After(replyTimeout):
+		return nil, errors.New("timed out waiting for reply")
+	}
+}
+
+// params makes sure an empty argument list encodes as [] rather than nil.
+func params(args []any) []any {
+	if args == nil {
+		return []any{}
+	}
+	return args
+}
+
+func (c *Client) readLoop() {
+	defer close(c.done)
+	dec := msgpack.NewDecoder(c.conn)
+	for {
+		v, err := dec.DecodeInterface()
+		if err != nil {
+			return
+		}
+		msg, ok := v.([]any)
+		if !ok || len(msg) < 3 {
+			continue
+		}
+		kind, _ := toInt(msg[0])
+		switch {
+		case kind == rpcResponse && len(msg) == 4:
+			c.handleResponse(msg[1], msg[2], msg[3])
+		case kind == rpcRequest && len(msg) == 4:
+			c.handleRequest(msg[1], msg[2], msg[3])
+		case kind == rpcNotification:
+			c.handleNotification(msg[1], msg[2])
+		}
+	}
+}
+
+// toInt accepts any of the integer types the decoder produces; msgpack
+// picks the narrowest encoding, so 1 arrives as int8 but 300 as uint16.
+func toInt(v any) (int64, bool) {
+	switch n := v.(type) {
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return int64(n), true
+	}
+	return 0, false
+}
+
+func (c *Client) handleResponse(id, rpcErr, result any) {
+	n, ok := toInt(id)
+	if !ok {
+		return
+	}
+	c.pendingMu.Lock()
+	ch, found := c.pending[uint32(n)]
+	delete(c.pending, uint32(n))
+	c.pendingMu.Unlock()
+	if found {
+		ch <- response{err: rpcErr, result: result}
+	}
+}
+
+// handleRequest answers rpcrequest calls from the plugin; errors are sent
+// back so Lua raises them.
+func (c *Client) handleRequest(id, method, args any) {
+	err := c.runCommand(method, args)
+	if err != nil {
+		c.send(rpcResponse, id, err.Error(), nil)
+		return
+	}
+	c.send(rpcResponse, id, nil, nil)
+}
+
+// handleNotification runs rpcnotify commands; nobody is waiting for a
+// reply, so errors are shown in Neovim's message area instead.
+func (c *Client) handleNotification(method, args any) {
+	if err := c.runCommand(method, args); err != nil {
+		c.notify("nvim_err_writeln", "workout-timer: "+err.Error())
+	}
+}
+
+func (c *Client) runCommand(method, args any) error {
+	if method != "command" {
+		return fmt.Errorf("unknown method: %v", method)
+	}
+	list, _ := args.([]any)
+	if len(list) != 1 {
+		return errors.New("command takes exactly one string argument")
+	}
+	command, ok := list[0].(string)
+	if !ok {
+		return errors.New("command takes exactly one string argument")
+	}
+	parsed, err := parser.ParseChain(command, c.defaultMode, c.macros)
+	if err != nil {
+		return err
+	}
+	c.dispatch(parsed)
+	return nil
+}
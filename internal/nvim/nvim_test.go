@@ -0,0 +1,155 @@
+package nvim
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// fakeNvim is a minimal msgpack-RPC peer standing in for Neovim. It answers
+// nvim_get_api_info and records every notification it receives.
+type fakeNvim struct {
+	path          string
+	conn          chan net.Conn
+	notifications chan []any
+	responses     chan []any
+}
+
+func startFakeNvim(t *testing.T) *fakeNvim {
+	t.Helper()
+	f := &fakeNvim{
+		path:          filepath.Join(t.TempDir(), "nvim.sock"),
+		conn:          make(chan net.Conn, 1),
+		notifications: make(chan []any, 10),
+		responses:     make(chan []any, 10),
+	}
+	ln, err := net.Listen("unix", f.path)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		f.conn <- conn
+		dec := msgpack.NewDecoder(conn)
+		enc := msgpack.NewEncoder(conn)
+		for {
+			v, err := dec.DecodeInterface()
+			if err != nil {
+				return
+			}
+			msg := v.([]any)
+			kind, _ := toInt(msg[0])
+			switch kind {
+			case rpcRequest:
+				if msg[2] == "nvim_get_api_info" {
+					enc.Encode([]any{rpcResponse, msg[1], nil, []any{7, map[string]any{}}})
+				}
+			case rpcResponse:
+				f.responses <- msg
+			case rpcNotification:
+				f.notifications <- msg
+			}
+		}
+	}()
+	return f
+}
+
+// expectNotification waits for the next notification, failing on timeout.
+func (f *fakeNvim) expectNotification(t *testing.T) (method string, args []any) {
+	t.Helper()
+	select {
+	case msg := <-f.notifications:
+		return msg[1].(string), msg[2].([]any)
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for notification")
+	}
+	return "", nil
+}
+
+func dialFake(t *testing.T) (*fakeNvim, *Client, chan string) {
+	t.Helper()
+	f := startFakeNvim(t)
+	got := make(chan string, 10)
+	c, err := Dial(f.path, types.ModeAuto, nil, func(command parser.Chain) { got <- command.String() })
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { c.Close() })
+	return f, c, got
+}
+
+func TestDialAnnouncesChannel(t *testing.T) {
+	f, _, _ := dialFake(t)
+
+	method, args := f.expectNotification(t)
+	if id, _ := toInt(args[1]); method != "nvim_set_var" || args[0] != "workout_timer_channel" || id != 7 {
+		t.Errorf("expected channel id to be stored, got %s %v", method, args)
+	}
+	method, args = f.expectNotification(t)
+	opts := args[1].(map[string]any)
+	if method != "nvim_exec_autocmds" || opts["pattern"] != "WorkoutTimerAttach" {
+		t.Errorf("expected attach autocmd, got %s %v", method, args)
+	}
+}
+
+func TestPublishFiresStateAutocmd(t *testing.T) {
+	f, c, _ := dialFake(t)
+	f.expectNotification(t) // nvim_set_var
+	f.expectNotification(t) // WorkoutTimerAttach
+
+	c.Publish(model.Snapshot{State: "running", Time: "0:42", Interval: 2, Intervals: 3})
+
+	method, args := f.expectNotification(t)
+	if method != "nvim_exec_autocmds" || args[0] != "User" {
+		t.Fatalf("expected User autocmd, got %s %v", method, args)
+	}
+	opts := args[1].(map[string]any)
+	if opts["pattern"] != "WorkoutTimerState" {
+		t.Errorf("expected WorkoutTimerState, got %v", opts["pattern"])
+	}
+	data := opts["data"].(map[string]any)
+	if interval, _ := toInt(data["interval"]); data["state"] != "running" || data["time"] != "0:42" || interval != 2 {
+		t.Errorf("unexpected data %v", data)
+	}
+}
+
+func TestCommandsFromNvim(t *testing.T) {
+	f, _, got := dialFake(t)
+	conn := <-f.conn
+	enc := msgpack.NewEncoder(conn)
+
+	enc.Encode([]any{rpcNotification, "command", []any{"next"}})
+	select {
+	case cmd := <-got:
+		if cmd != "next" {
+			t.Errorf("expected next, got %q", cmd)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for dispatch")
+	}
+
+	enc.Encode([]any{rpcRequest, 1, "command", []any{"fly"}})
+	select {
+	case msg := <-f.responses:
+		if msg[2] != `unknown command: "fly"` {
+			t.Errorf("expected error response, got %v", msg)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for response")
+	}
+	if len(got) != 0 {
+		t.Errorf("invalid command should not dispatch, got %q", <-got)
+	}
+}
@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// Chain is a command line: commands run one after another, written
+// separated by ";".
+type Chain []Command
+
+func (c Chain) String() string {
+	texts := make([]string, len(c))
+	for i, command := range c {
+		texts[i] = command.Text
+	}
+	return strings.Join(texts, "; ")
+}
+
+// ParseChain parses a command line such as "set 5:00; start". Any command
+// may be the name of one of macros, which maps names to command lines of
+// their own; these are expanded in place. Commands shadow macros of the
+// same name. Empty commands, as after a trailing ";", are skipped, but
+// a line with no command at all is an error. Errors are *Error located in input, a problem inside a macro
+// being placed on its name.
+func ParseChain(input string, defaultMode types.Mode, macros map[string]string) (Chain, error) {
+	return parseChain(input, defaultMode, macros, nil)
+}
+
+// parseChain parses input inside the macros named by calling, outermost
+// first, so that a macro reached again is caught before it loops.
+func parseChain(input string, defaultMode types.Mode, macros map[string]string, calling []string) (Chain, error) {
+	var chain Chain
+	offset := 0 // of the segment in input
+	for _, segment := range strings.Split(input, ";") {
+		start := offset
+		offset += len(segment) + 1
+
+		tokens := tokenize(segment)
+		if len(tokens) == 0 {
+			continue
+		}
+		if !isMacro(tokens[0].text, macros) {
+			c, err := Parse(segment, defaultMode)
+			if err != nil {
+				return nil, shift(suggestMacros(err, tokens[0].text, macros), start)
+			}
+			chain = append(chain, c)
+			continue
+		}
+
+		name := token{tokens[0].text, start + tokens[0].start}
+		if len(tokens) > 1 {
+			return nil, shift(spanOf(tokens[1:], "macro %s takes no arguments", name.text), start)
+		}
+		if slices.Contains(calling, name.text) {
+			path := strings.Join(append(slices.Clone(calling), name.text), " → ")
+			return nil, errorAt(name, "macro %q calls itself (%s)", name.text, path)
+		}
+		expanded, err := parseChain(macros[name.text], defaultMode, macros, append(slices.Clone(calling), name.text))
+		if err != nil {
+			return nil, inMacro(err, name)
+		}
+		chain = append(chain, expanded...)
+	}
+	if len(chain) == 0 {
+		return nil, missingAt(input, verbs, "empty command")
+	}
+	return chain, nil
+}
+
+// suggestMacros adds the macro names to what an unknown command could
+// have been. Other errors are returned as is.
+func suggestMacros(err error, word string, macros map[string]string) error {
+	e, ok := err.(*Error)
+	if _, known := lookupVerb(word); !ok || known || len(macros) == 0 {
+		return err
+	}
+	names := slices.Sorted(maps.Keys(macros))
+	e.Expected = append(slices.Clone(verbs), names...)
+	e.Suggestions = suggest(word, e.Expected)
+	return e
+}
+
+func isMacro(name string, macros map[string]string) bool {
+	if _, ok := lookupVerb(name); ok {
+		return false
+	}
+	_, ok := macros[name]
+	return ok
+}
+
+// inMacro places an error from inside a macro on its name in the line
+// that called it, noting the innermost macro it came from.
+func inMacro(err error, name token) error {
+	e, ok := err.(*Error)
+	if !ok {
+		return err
+	}
+	if e.Macro == "" {
+		e.Macro = name.text
+	}
+	e.Start, e.End = name.start, name.end()
+	return e
+}
+
+// CheckMacros reports macros that can never run: those hidden by a
+// command of the same name, and those whose commands don't parse.
+func CheckMacros(macros map[string]string, defaultMode types.Mode) error {
+	var problems []string
+	for name := range macros {
+		if _, ok := lookupVerb(name); ok {
+			problems = append(problems, fmt.Sprintf("macro %q is hidden by the command of the same name", name))
+			continue
+		}
+		if len(tokenize(name)) != 1 || strings.Contains(name, ";") {
+			problems = append(problems, fmt.Sprintf("macro name %q must be a single word", name))
+			continue
+		}
+		if _, err := ParseChain(name, defaultMode, macros); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	sort.Strings(problems)
+	return errors.New(strings.Join(problems, "; "))
+}
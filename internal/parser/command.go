@@ -0,0 +1,133 @@
+package parser
+
+import (
+	"time"
+
+	prog "github.com/BobbyGerace/workout-timer/internal/program"
+	"github.com/BobbyGerace/workout-timer/internal/timer"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// Verb identifies what a Command does.
+type Verb int
+
+const (
+	VerbQuit Verb = iota
+	VerbStart
+	VerbNext
+	VerbPause
+	VerbResume
+	VerbBack
+	VerbReset
+	VerbClear
+	VerbStatus
+	VerbStopwatch
+	VerbMute
+	VerbUnmute
+	VerbHelp
+	VerbPrompt
+	VerbAdd
+	VerbSubtract
+	VerbSet
+	VerbFont
+	VerbVolume
+)
+
+var verbNames = []string{
+	VerbQuit:      "quit",
+	VerbStart:     "start",
+	VerbNext:      "next",
+	VerbPause:     "pause",
+	VerbResume:    "resume",
+	VerbBack:      "back",
+	VerbReset:     "reset",
+	VerbClear:     "clear",
+	VerbStatus:    "status",
+	VerbStopwatch: "stopwatch",
+	VerbMute:      "mute",
+	VerbUnmute:    "unmute",
+	VerbHelp:      "help",
+	VerbPrompt:    "prompt",
+	VerbAdd:       "add",
+	VerbSubtract:  "subtract",
+	VerbSet:       "set",
+	VerbFont:      "font",
+	VerbVolume:    "volume",
+}
+
+// aliases are extra names for verbs.
+var aliases = map[string]Verb{"q": VerbQuit}
+
+func (v Verb) String() string {
+	if v < 0 || int(v) >= len(verbNames) {
+		return "unknown"
+	}
+	return verbNames[v]
+}
+
+// Verbs returns every verb, in declaration order.
+func Verbs() []Verb {
+	out := make([]Verb, len(verbNames))
+	for i := range out {
+		out[i] = Verb(i)
+	}
+	return out
+}
+
+// lookupVerb returns the verb called name, accepting aliases.
+func lookupVerb(name string) (Verb, bool) {
+	if v, ok := aliases[name]; ok {
+		return v, true
+	}
+	for i, n := range verbNames {
+		if n == name {
+			return Verb(i), true
+		}
+	}
+	return 0, false
+}
+
+// Command is a parsed command, ready to execute. Only the fields its Verb
+// takes are set.
+type Command struct {
+	Verb Verb
+	Text string // the command as written, trimmed
+
+	Duration time.Duration // add, subtract
+	Name     string        // font
+
+	// set
+	Mode      types.Mode
+	Intervals []time.Duration
+	Labels    []string // by interval, or nil when none is labelled
+	Rounds    int      // 0 loops forever
+
+	Volume Volume // volume
+}
+
+func (c Command) String() string { return c.Text }
+
+// Program returns a new program for a set command, ready to start.
+func (c Command) Program() prog.Program {
+	t := timer.New(c.Intervals, c.Rounds, c.Mode)
+	if c.Labels != nil {
+		t.WithLabels(c.Labels)
+	}
+	return t
+}
+
+// Volume is a level in percent, or a change to the current level.
+type Volume struct {
+	Level    int
+	Relative bool
+}
+
+// From returns the level to set when the current one is current, clamped
+// to 0–100.
+func (v Volume) From(current int) int {
+	n := v.Level
+	if v.Relative {
+		n += current
+	}
+	return max(0, min(100, n))
+}
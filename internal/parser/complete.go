@@ -0,0 +1,90 @@
+package parser
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// verbs lists the names of the verbs, less aliases, sorted for completion.
+var verbs = func() []string {
+	names := slices.Clone(verbNames)
+	sort.Strings(names)
+	return names
+}()
+
+// modes are the words that may follow "set".
+var modes = []string{"auto", "manual"}
+
+// Complete returns the lines input can be completed to, sorted. The first
+// word completes to a verb or to one of words[""], e.g. macro names; after
+// "set" the rest of the line completes to a mode or to one of words["set"],
+// which holds whole argument strings such as "auto 1:30,60 x3"; after any
+// other verb the next word completes to one of words[verb], e.g. font
+// names. In a chain only the last command is completed.
+func Complete(input string, words map[string][]string) []string {
+	if i := strings.LastIndex(input, ";"); i >= 0 {
+		lines := Complete(input[i+1:], words)
+		for j, line := range lines {
+			lines[j] = input[:i+1] + " " + line
+		}
+		return lines
+	}
+
+	input = strings.TrimLeft(input, " ")
+	verb, rest, hasArgs := strings.Cut(input, " ")
+	if !hasArgs {
+		return matches("", verb, append(slices.Clone(verbs), words[""]...))
+	}
+
+	rest = strings.TrimLeft(rest, " ")
+	switch verb {
+	case "set":
+		candidates := append(append([]string{}, modes...), words["set"]...)
+		// "set auto 1:" completes presets given without a mode too
+		for _, mode := range modes {
+			if strings.HasPrefix(rest, mode+" ") {
+				for _, preset := range words["set"] {
+					if !strings.HasPrefix(preset, "auto ") && !strings.HasPrefix(preset, "manual ") {
+						candidates = append(candidates, mode+" "+preset)
+					}
+				}
+			}
+		}
+		return matches("set ", rest, candidates)
+	default:
+		if strings.Contains(rest, " ") {
+			return nil
+		}
+		return matches(verb+" ", rest, words[verb])
+	}
+}
+
+// matches returns head+c for every candidate c starting with prefix,
+// without duplicates.
+func matches(head, prefix string, candidates []string) []string {
+	seen := map[string]bool{}
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			out = append(out, head+c)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// CommonPrefix returns the longest prefix shared by every line.
+func CommonPrefix(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	prefix := lines[0]
+	for _, line := range lines[1:] {
+		for !strings.HasPrefix(line, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Error is a parse error located in the input, so a prompt can point at
+// the offending text.
+type Error struct {
+	Msg         string
+	Start, End  int      // byte span in the input; empty at the end for missing tokens
+	Expected    []string // what would have been accepted there
+	Suggestions []string // near misses for a misspelt word, e.g. "next" for "nxt"
+	Macro       string   // the macro the problem is in, whose name the span covers
+}
+
+func (e *Error) Error() string {
+	msg := e.Msg
+	if e.Macro != "" {
+		msg = fmt.Sprintf("in macro %q: %s", e.Macro, msg)
+	}
+	switch {
+	case len(e.Suggestions) > 0:
+		return fmt.Sprintf("%s (did you mean %s?)", msg, orList(e.Suggestions))
+	case len(e.Expected) > 0 && len(e.Expected) <= maxExpected:
+		return fmt.Sprintf("%s (expected %s)", msg, orList(e.Expected))
+	}
+	return msg
+}
+
+// maxExpected is the most alternatives an error message lists; longer
+// lists, like every verb, say nothing useful.
+const maxExpected = 4
+
+func errorSpan(start, end int, format string, args ...any) *Error {
+	return &Error{Msg: fmt.Sprintf(format, args...), Start: start, End: end}
+}
+
+// errorAt returns an Error spanning tok.
+func errorAt(tok token, format string, args ...any) *Error {
+	return errorSpan(tok.start, tok.end(), format, args...)
+}
+
+// spanOf returns an Error spanning the run of tokens, e.g. surplus
+// arguments.
+func spanOf(tokens []token, format string, args ...any) *Error {
+	return errorSpan(tokens[0].start, tokens[len(tokens)-1].end(), format, args...)
+}
+
+// missingAt returns an Error for something absent at the end of input.
+func missingAt(input string, expected []string, format string, args ...any) *Error {
+	end := len(strings.TrimRightFunc(input, unicode.IsSpace))
+	return &Error{Msg: fmt.Sprintf(format, args...), Start: end, End: end, Expected: expected}
+}
+
+// shift moves the span of an error from a token's own parser, such as
+// ParseDuration, into the whole input. Other errors are returned as is.
+func shift(err error, offset int) error {
+	if e, ok := err.(*Error); ok {
+		e.Start += offset
+		e.End += offset
+	}
+	return err
+}
+
+// token is a whitespace-separated word and where it starts in the input.
+type token struct {
+	text  string
+	start int
+}
+
+func (t token) end() int { return t.start + len(t.text) }
+
+// tokenize splits s like strings.Fields, keeping positions.
+func tokenize(s string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r) && start >= 0:
+			tokens = append(tokens, token{s[start:i], start})
+			start = -1
+		case !unicode.IsSpace(r) && start < 0:
+			start = i
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, token{s[start:], start})
+	}
+	return tokens
+}
+
+// suggest returns the candidates within a small edit distance of word,
+// closest first.
+func suggest(word string, candidates []string) []string {
+	limit := max(1, min(2, len(word)/3))
+	var out []string
+	for d := 1; d <= limit; d++ {
+		for _, c := range candidates {
+			if distance(word, c) == d {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// distance is the Levenshtein distance between a and b, counting an
+// adjacent transposition as one edit.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
+
+// orList joins words as `"a", "b" or "c"`.
+func orList(words []string) string {
+	quoted := make([]string, len(words))
+	for i, w := range words {
+		quoted[i] = fmt.Sprintf("%q", w)
+		if strings.HasPrefix(w, "<") {
+			quoted[i] = w // placeholders such as <duration> are not literal
+		}
+	}
+	if len(quoted) == 1 {
+		return quoted[0]
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + " or " + quoted[len(quoted)-1]
+}
@@ -1,21 +1,107 @@
 package parser
 
 import (
-	"fmt"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
 	prog "github.com/BobbyGerace/workout-timer/internal/program"
-	"github.com/BobbyGerace/workout-timer/internal/timer"
+	"github.com/BobbyGerace/workout-timer/internal/renderer"
 	"github.com/BobbyGerace/workout-timer/internal/types"
 )
 
+// Parse parses a command from the prompt, the FIFO, the socket or any other
+// source into a Command. Errors are *Error, locating the problem in input.
+// This is the one parser shared by everything that runs commands.
+//
+// Font names are checked against the renderer's registry, so user fonts
+// must be registered with renderer.LoadFonts before anything is parsed.
+func Parse(input string, defaultMode types.Mode) (Command, error) {
+	tokens := tokenize(input)
+	if len(tokens) == 0 {
+		return Command{}, missingAt(input, verbs, "empty command")
+	}
+	word, args := tokens[0], tokens[1:]
+	verb, ok := lookupVerb(word.text)
+	if !ok {
+		e := errorAt(word, "unknown command: %q", word.text)
+		e.Expected = verbs
+		e.Suggestions = suggest(word.text, verbs)
+		return Command{}, e
+	}
+	c := Command{Verb: verb, Text: strings.TrimSpace(input)}
+
+	switch verb {
+	case VerbAdd, VerbSubtract:
+		if len(args) == 0 {
+			return Command{}, missingAt(input, []string{"<duration>"}, "%s requires a duration (e.g. 30 or 1:30)", verb)
+		}
+		if len(args) > 1 {
+			return Command{}, spanOf(args[1:], "%s takes a single duration", verb)
+		}
+		d, err := ParseDuration(args[0].text)
+		if err != nil {
+			return Command{}, shift(err, args[0].start)
+		}
+		if d <= 0 {
+			return Command{}, errorAt(args[0], "duration must be positive: %q", args[0].text)
+		}
+		c.Duration = d
+
+	case VerbSet:
+		return parseSet(c, args, input, defaultMode)
+
+	case VerbFont:
+		if len(args) == 0 {
+			return Command{}, missingAt(input, []string{"<name>"}, "font requires a name")
+		}
+		if len(args) > 1 {
+			return Command{}, spanOf(args[1:], "font takes a single name")
+		}
+		if _, ok := renderer.Lookup(args[0].text); !ok {
+			fonts := renderer.Fonts()
+			e := errorAt(args[0], "unknown font %q", args[0].text)
+			e.Expected = fonts
+			e.Suggestions = suggest(args[0].text, fonts)
+			return Command{}, e
+		}
+		c.Name = args[0].text
+
+	case VerbVolume:
+		if len(args) == 0 {
+			return Command{}, missingAt(input, []string{"<level>"}, "volume requires a level (e.g. 80, +10 or -10)")
+		}
+		if len(args) > 1 {
+			return Command{}, spanOf(args[1:], "volume takes a single level")
+		}
+		v, err := parseVolume(args[0].text)
+		if err != nil {
+			return Command{}, shift(err, args[0].start)
+		}
+		c.Volume = v
+
+	default:
+		if len(args) != 0 {
+			return Command{}, spanOf(args, "%s takes no arguments", verb)
+		}
+	}
+	return c, nil
+}
+
+// ParseCommand validates a command string without executing it.
+// Returns nil if the command is syntactically valid, or an *Error describing the problem.
+func ParseCommand(input string, defaultMode types.Mode) error {
+	_, err := Parse(input, defaultMode)
+	return err
+}
+
 // ParseSet parses a "set" command with the full grammar and returns a ready-to-use Program.
 //
 // Grammar:
 //
-//	set [auto|manual] <t1>[,<t2>,...] [xN]
+//	set [auto|manual] [label=]<t1>[,[label=]<t2>,...] [xN]
 //
 // Examples:
 //
@@ -23,144 +109,205 @@ import (
 //	set auto 1:30
 //	set manual 60 x5
 //	set auto 1:30,60,4:00 x3
+//	set auto work=45,rest=15 x8
 //
 // When no mode flag is given, defaultMode is used.
 // When no round count is given, rounds defaults to 0 (loop forever).
 func ParseSet(input string, defaultMode types.Mode) (prog.Program, error) {
-	fields := strings.Fields(input)
-	if len(fields) < 2 || fields[0] != "set" {
-		return nil, fmt.Errorf("usage: set [auto|manual] <duration>[,...] [xN]")
+	tokens := tokenize(input)
+	if len(tokens) == 0 || tokens[0].text != "set" {
+		return nil, errorSpan(0, len(input), "usage: set [auto|manual] <duration>[,...] [xN]")
+	}
+	c, err := parseSet(Command{Verb: VerbSet, Text: strings.TrimSpace(input)}, tokens[1:], input, defaultMode)
+	if err != nil {
+		return nil, err
 	}
-	tokens := fields[1:] // drop "set"
+	return c.Program(), nil
+}
 
-	mode := defaultMode
-	switch tokens[0] {
-	case "manual":
-		mode = types.ModeManual
-		tokens = tokens[1:]
-	case "auto":
-		mode = types.ModeAuto
-		tokens = tokens[1:]
+// parseSet fills in c from the arguments of a set command.
+func parseSet(c Command, tokens []token, input string, defaultMode types.Mode) (Command, error) {
+	mode, explicit := defaultMode, false
+	if len(tokens) > 0 {
+		switch tokens[0].text {
+		case "manual":
+			mode, explicit = types.ModeManual, true
+			tokens = tokens[1:]
+		case "auto":
+			mode, explicit = types.ModeAuto, true
+			tokens = tokens[1:]
+		}
 	}
 
 	// head should now be intervals
 	if len(tokens) == 0 {
-		return nil, fmt.Errorf("Missing duration")
+		expected := []string{"<duration>"}
+		if !explicit {
+			expected = append(slices.Clone(modes), expected...)
+		}
+		return Command{}, missingAt(input, expected, "missing duration")
 	}
 
-	intervals, err := parseDurationList(tokens[0])
+	intervals, labels, err := parseDurationList(tokens[0].text)
 	if err != nil {
-		return nil, err
+		err = shift(err, tokens[0].start)
+		// a word where the mode goes is more likely a misspelt mode
+		if e, ok := err.(*Error); ok && !explicit && !strings.ContainsAny(tokens[0].text, "0123456789:,=") {
+			e.Expected = append(slices.Clone(modes), "<duration>")
+			e.Suggestions = suggest(tokens[0].text, modes)
+		}
+		return Command{}, err
 	}
 
 	rounds := 0
 	if len(tokens) > 1 {
-		rounds, err = parseRounds(tokens[1])
+		rounds, err = parseRounds(tokens[1].text)
 		if err != nil {
-			return nil, err
+			return Command{}, shift(err, tokens[1].start)
 		}
 	}
+	if len(tokens) > 2 {
+		return Command{}, spanOf(tokens[2:], "unexpected %q after the round count", tokens[2].text)
+	}
 
-	return timer.New(intervals, rounds, mode), nil
+	c.Mode, c.Intervals, c.Labels, c.Rounds = mode, intervals, labels, rounds
+	return c, nil
 }
 
-// ParseCommand validates a command string without executing it.
-// Returns nil if the command is syntactically valid, or an error describing the problem.
-// This is the canonical validator shared by the prompt, FIFO listener, and CLI.
-func ParseCommand(input string, defaultMode types.Mode) error {
-	input = strings.TrimSpace(input)
-	if input == "" {
-		return fmt.Errorf("empty command")
+// ParseVolume parses a volume level in percent, either absolute ("80") or
+// relative to current ("+10", "-10"). The result is clamped to 0–100.
+func ParseVolume(s string, current int) (int, error) {
+	v, err := parseVolume(s)
+	if err != nil {
+		return 0, err
 	}
-	fields := strings.Fields(input)
-	verb := fields[0]
-
-	switch verb {
-	case "quit", "q", "start", "next", "pause", "resume", "back",
-		"reset", "clear", "status", "stopwatch":
-		if len(fields) != 1 {
-			return fmt.Errorf("%s takes no arguments", verb)
-		}
-		return nil
-
-	case "add", "subtract":
-		if len(fields) != 2 {
-			return fmt.Errorf("%s requires a duration (e.g. 30 or 1:30)", verb)
-		}
-		d, err := ParseDuration(fields[1])
-		if err != nil || d <= 0 {
-			return fmt.Errorf("invalid duration: %q", fields[1])
-		}
-		return nil
-
-	case "set":
-		_, err := ParseSet(input, defaultMode)
-		return err
+	return v.From(current), nil
+}
 
-	default:
-		return fmt.Errorf("unknown command: %q", verb)
+func parseVolume(s string) (Volume, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil || (n < 0 || n > 100) && s[0] != '+' && s[0] != '-' {
+		return Volume{}, errorSpan(0, len(s), "invalid volume: %q (expected 0-100, +N or -N)", s)
 	}
+	return Volume{Level: n, Relative: s[0] == '+' || s[0] == '-'}, nil
 }
 
 // parseDurationList splits a comma-separated duration string and parses each segment.
-func parseDurationList(s string) ([]time.Duration, error) {
+// A segment may be prefixed with "label="; the labels are returned by position,
+// or nil when no segment has one.
+func parseDurationList(s string) ([]time.Duration, []string, error) {
 	parts := strings.Split(s, ",")
 	durations := make([]time.Duration, 0, len(parts))
-	for _, p := range parts {
-		d, err := ParseDuration(strings.TrimSpace(p))
+	labels := make([]string, len(parts))
+	labelled := false
+	offset := 0 // of the part in s
+	for i, p := range parts {
+		start := offset
+		offset += len(p) + 1
+		if label, rest, ok := strings.Cut(p, "="); ok {
+			if label == "" {
+				return nil, nil, errorSpan(start, start+1, "missing label before %q", "="+rest)
+			}
+			labels[i], p, labelled = label, rest, true
+			start += len(label) + 1
+		}
+		if p == "" {
+			return nil, nil, errorSpan(start, start, "missing duration")
+		}
+		d, err := ParseDuration(p)
 		if err != nil {
-			return nil, err
+			return nil, nil, shift(err, start)
 		}
 		durations = append(durations, d)
 	}
-	return durations, nil
+	if !labelled {
+		labels = nil
+	}
+	return durations, labels, nil
 }
 
 // ParseDuration converts a duration string into a time.Duration.
-// Accepts plain seconds ("90") or m:ss format ("1:30").
+// Accepts plain seconds ("90"), m:ss ("1:30", "90:00"), h:mm:ss ("1:30:00")
+// or unit suffixes ("90s", "2m", "1h30m", "1h5m30s").
 // Returns an error for invalid input (non-numeric, bad m:ss format, seconds >= 60).
 func ParseDuration(s string) (time.Duration, error) {
+	if m := unitDuration.FindStringSubmatch(s); m != nil && s != "" {
+		var total time.Duration
+		for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+			if m[i+1] == "" {
+				continue
+			}
+			n, err := strconv.Atoi(m[i+1])
+			if err != nil {
+				return 0, errorSpan(0, len(s), "invalid duration: %q", s)
+			}
+			total += time.Duration(n) * unit
+		}
+		return total, nil
+	}
+
 	fields := strings.Split(s, ":")
-	if len(fields) == 2 {
+	switch len(fields) {
+	case 3:
+		hours, err := strconv.Atoi(fields[0])
+		if err != nil || hours < 0 {
+			return 0, errorSpan(0, len(fields[0]), "invalid hours: %q", fields[0])
+		}
+		if len(fields[1]) != 2 || len(fields[2]) != 2 {
+			return 0, errorSpan(0, len(s), "invalid duration: %q (expected h:mm:ss)", s)
+		}
+		offset := len(fields[0]) + 1
+		rest, err := ParseDuration(fields[1] + ":" + fields[2])
+		if err != nil {
+			return 0, shift(err, offset)
+		}
+		if rest >= time.Hour {
+			return 0, errorSpan(offset, offset+len(fields[1]), "minutes out of range: %q", fields[1])
+		}
+		return time.Duration(hours)*time.Hour + rest, nil
+
+	case 2:
 		minutes, err := strconv.Atoi(fields[0])
 		if err != nil {
-			return 0, fmt.Errorf("Invalid minutes: %s", fields[0])
-		} else if minutes < 0 || minutes > 59 {
-			return 0, fmt.Errorf("Minutes out of range: %s", fields[0])
+			return 0, errorSpan(0, len(fields[0]), "invalid minutes: %q", fields[0])
+		} else if minutes < 0 {
+			return 0, errorSpan(0, len(fields[0]), "minutes out of range: %q", fields[0])
 		}
 
+		offset := len(fields[0]) + 1
 		seconds, err := strconv.Atoi(fields[1])
 		if err != nil {
-			return 0, fmt.Errorf("Invalid seconds: %s", fields[1])
+			return 0, errorSpan(offset, len(s), "invalid seconds: %q", fields[1])
 		} else if seconds < 0 || seconds > 59 {
-			return 0, fmt.Errorf("Seconds out of range: %s", fields[1])
+			return 0, errorSpan(offset, len(s), "seconds out of range: %q", fields[1])
 		}
 
 		return time.Duration(minutes*60+seconds) * time.Second, nil
-	} else if len(fields) == 1 {
+
+	case 1:
 		seconds, err := strconv.Atoi(fields[0])
 		if err != nil || seconds < 0 {
-			return 0, fmt.Errorf("Invalid duration: %s", fields[0])
+			return 0, errorSpan(0, len(s), "invalid duration: %q", s)
 		}
 
 		return time.Duration(seconds) * time.Second, nil
-	} else {
-		return 0, fmt.Errorf("Invalid duration syntax")
 	}
+	return 0, errorSpan(0, len(s), "invalid duration: %q", s)
 }
 
+// unitDuration matches durations written with h, m and s suffixes, each
+// used at most once and in that order.
+var unitDuration = regexp.MustCompile(`^(?:(\d+)h)?(?:(\d+)m)?(?:(\d+)s)?$`)
+
 // parseRounds parses an "xN" token and returns N.
 // Returns an error if the format is invalid or N < 1.
 func parseRounds(s string) (int, error) {
 	if len(s) < 2 || s[0] != 'x' {
-		return 0, fmt.Errorf("invalid round count %q: expected format xN (e.g. x3)", s)
+		return 0, errorSpan(0, len(s), "invalid round count %q: expected format xN (e.g. x3)", s)
 	}
 	n, err := strconv.Atoi(s[1:])
 	if err != nil || n < 1 {
-		return 0, fmt.Errorf("invalid round count %q: N must be a positive integer", s)
+		return 0, errorSpan(0, len(s), "invalid round count %q: N must be a positive integer", s)
 	}
 	return n, nil
 }
-
-// ensure *timer.Timer satisfies prog.Program at compile time
-var _ prog.Program = (*timer.Timer)(nil)
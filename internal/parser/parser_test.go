@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -27,6 +28,12 @@ func TestParseSet(t *testing.T) {
 		{"set 1:30", auto, false, 90, 1, 0},
 		{"set 0", auto, false, 0, 1, 0},
 		{"set 10:00", auto, false, 600, 1, 0},
+		{"set 90:00", auto, false, 5400, 1, 0},
+		{"set 1:30:00", auto, false, 5400, 1, 0},
+		{"set 1h30m", auto, false, 5400, 1, 0},
+		{"set 90s", auto, false, 90, 1, 0},
+		{"set 2m", auto, false, 120, 1, 0},
+		{"set 1h5m30s", auto, false, 3930, 1, 0},
 
 		// ── Mode flag ─────────────────────────────────────────────────────
 		{"set auto 60", auto, false, 60, 1, 0},
@@ -44,6 +51,8 @@ func TestParseSet(t *testing.T) {
 		{"set 90,60", auto, false, 90, 2, 0},
 		{"set auto 1:30,60,4:00", auto, false, 90, 3, 0},
 		{"set auto 1:30,60,4:00 x3", auto, false, 90, 3, 3},
+		{"set auto work=45,rest=15 x8", auto, false, 45, 2, 8},
+		{"set warmup=5m", auto, false, 300, 1, 0},
 
 		// ── Errors: missing keyword / duration ────────────────────────────
 		{"90", auto, true, 0, 0, 0},
@@ -55,7 +64,14 @@ func TestParseSet(t *testing.T) {
 		{"set 1:60", auto, true, 0, 0, 0},
 		{"set -1", auto, true, 0, 0, 0},
 		{"set 1:2:3", auto, true, 0, 0, 0},
+		{"set 1:60:00", auto, true, 0, 0, 0},
+		{"set 1:00:60", auto, true, 0, 0, 0},
+		{"set 30m1h", auto, true, 0, 0, 0},
+		{"set 1.5h", auto, true, 0, 0, 0},
+		{"set 10ms", auto, true, 0, 0, 0},
 		{"set 90,abc", auto, true, 0, 0, 0},
+		{"set =45", auto, true, 0, 0, 0},
+		{"set work=", auto, true, 0, 0, 0},
 
 		// ── Errors: bad round count ────────────────────────────────────────
 		{"set 60 x0", auto, true, 0, 0, 0},
@@ -131,6 +147,10 @@ func TestParseCommand(t *testing.T) {
 		{"clear", false},
 		{"status", false},
 		{"stopwatch", false},
+		{"mute", false},
+		{"unmute", false},
+		{"help", false},
+		{"prompt", false},
 
 		// ── add / subtract ────────────────────────────────────────────────
 		{"add 30", false},
@@ -148,6 +168,20 @@ func TestParseCommand(t *testing.T) {
 		{"set auto 1:30,60 x3", false},
 		{"set bad", true},
 
+		// ── font ──────────────────────────────────────────────────────────
+		{"font square", false},
+		{"font", true},
+		{"font a b", true},
+		{"font comic", true},
+
+		// ── volume ────────────────────────────────────────────────────────
+		{"volume 80", false},
+		{"volume +10", false},
+		{"volume -10", false},
+		{"volume", true},
+		{"volume 150", true},
+		{"volume loud", true},
+
 		// ── no-arg commands with extra args ───────────────────────────────
 		{"quit now", true},
 		{"reset all", true},
@@ -185,7 +219,7 @@ func TestParseDurationList(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			got, err := parseDurationList(tt.input)
+			got, _, err := parseDurationList(tt.input)
 			if tt.wantErr {
 				if err == nil {
 					t.Errorf("expected error, got nil")
@@ -209,6 +243,27 @@ func TestParseDurationList(t *testing.T) {
 	}
 }
 
+func TestParseLabels(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"45,15", nil},
+		{"work=45,rest=15", []string{"work", "rest"}},
+		{"warmup=5m,45,rest=15", []string{"warmup", "", "rest"}},
+	}
+	for _, tt := range tests {
+		_, got, err := parseDurationList(tt.input)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
 func TestParseRounds(t *testing.T) {
 	tests := []struct {
 		input   string
@@ -244,3 +299,278 @@ func TestParseRounds(t *testing.T) {
 		})
 	}
 }
+
+func TestParseVolume(t *testing.T) {
+	tests := []struct {
+		input   string
+		current int
+		wantErr bool
+		want    int
+	}{
+		{"80", 50, false, 80},
+		{"0", 50, false, 0},
+		{"+10", 50, false, 60},
+		{"-10", 50, false, 40},
+		{"+30", 90, false, 100},
+		{"-30", 20, false, 0},
+		{"101", 50, true, 0},
+		{"-", 50, true, 0},
+		{"", 50, true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseVolume(tt.input, tt.current)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected error for %q, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("got %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	words := map[string][]string{
+		"font": {"bevelled", "powerline", "square"},
+		"set":  {"1:00", "10:00", "auto 1:30,60 x3"},
+	}
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"", verbs},
+		{"s", []string{"set", "start", "status", "stopwatch", "subtract"}},
+		{"st", []string{"start", "status", "stopwatch"}},
+		{"nex", []string{"next"}},
+		{"font ", []string{"font bevelled", "font powerline", "font square"}},
+		{"font s", []string{"font square"}},
+		{"font square ", nil},
+		{"set ", []string{"set 10:00", "set 1:00", "set auto", "set auto 1:30,60 x3", "set manual"}},
+		{"set a", []string{"set auto", "set auto 1:30,60 x3"}},
+		{"set manual 1", []string{"set manual 10:00", "set manual 1:00"}},
+		{"set 1", []string{"set 10:00", "set 1:00"}},
+		{"pause ", nil},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		if got := Complete(tt.input, words); !slices.Equal(got, tt.want) {
+			t.Errorf("Complete(%q): got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestCommonPrefix(t *testing.T) {
+	if got := CommonPrefix([]string{"set 1:00", "set 10:00"}); got != "set 1" {
+		t.Errorf("got %q", got)
+	}
+	if got := CommonPrefix(nil); got != "" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input       string
+		start, end  int
+		suggestions []string
+		msg         string
+	}{
+		{"nxt", 0, 3, []string{"next"}, `unknown command: "nxt" (did you mean "next"?)`},
+		{"  pase", 2, 6, []string{"pause"}, `unknown command: "pase" (did you mean "pause"?)`},
+		{"fly", 0, 3, nil, `unknown command: "fly"`},
+		{"pause now please", 6, 16, nil, "pause takes no arguments"},
+		{"add", 3, 3, nil, "add requires a duration (e.g. 30 or 1:30) (expected <duration>)"},
+		{"add 0", 4, 5, nil, `duration must be positive: "0"`},
+		{"add 1:7x", 6, 8, nil, `invalid seconds: "7x"`},
+		{"set", 3, 3, nil, `missing duration (expected "auto", "manual" or <duration>)`},
+		{"set auto ", 8, 8, nil, "missing duration (expected <duration>)"},
+		{"set atuo 1:00", 4, 8, []string{"auto"}, `invalid duration: "atuo" (did you mean "auto"?)`},
+		{"set 1:60", 6, 8, nil, `seconds out of range: "60"`},
+		{"set 90,abc x3", 7, 10, nil, `invalid duration: "abc"`},
+		{"set 1:60:00", 6, 8, nil, `minutes out of range: "60"`},
+		{"set work=45,=15", 12, 13, nil, `missing label before "=15"`},
+		{"set work=", 9, 9, nil, "missing duration"},
+		{"set 60 x0", 7, 9, nil, `invalid round count "x0": N must be a positive integer`},
+		{"set 60 x3 now", 10, 13, nil, `unexpected "now" after the round count`},
+		{"volume 80 90", 10, 12, nil, "volume takes a single level"},
+		{"font comic", 5, 10, nil, `unknown font "comic" (expected "bevelled", "powerline" or "square")`},
+		{"font sqaure", 5, 11, []string{"square"}, `unknown font "sqaure" (did you mean "square"?)`},
+		{"volume loud", 7, 11, nil, `invalid volume: "loud" (expected 0-100, +N or -N)`},
+	}
+	for _, tt := range tests {
+		err := ParseCommand(tt.input, types.ModeAuto)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%q: expected *Error, got %v", tt.input, err)
+			continue
+		}
+		if e.Start != tt.start || e.End != tt.end {
+			t.Errorf("%q: expected span %d-%d, got %d-%d", tt.input, tt.start, tt.end, e.Start, e.End)
+		}
+		if !slices.Equal(e.Suggestions, tt.suggestions) {
+			t.Errorf("%q: expected suggestions %q, got %q", tt.input, tt.suggestions, e.Suggestions)
+		}
+		if e.Error() != tt.msg {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.msg, e.Error())
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		word string
+		want []string
+	}{
+		{"nxt", []string{"next"}},
+		{"nexxt", []string{"next"}},
+		{"rsume", []string{"resume"}},
+		{"stat", []string{"start"}},
+		{"stauts", []string{"status", "start"}},
+		{"umnute", []string{"unmute", "mute"}},
+		{"xyz", nil},
+	}
+	for _, tt := range tests {
+		if got := suggest(tt.word, verbs); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: expected %q, got %q", tt.word, tt.want, got)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Command
+	}{
+		{"q", Command{Verb: VerbQuit, Text: "q"}},
+		{"  next ", Command{Verb: VerbNext, Text: "next"}},
+		{"add 1:30", Command{Verb: VerbAdd, Text: "add 1:30", Duration: 90 * time.Second}},
+		{"subtract 30", Command{Verb: VerbSubtract, Text: "subtract 30", Duration: 30 * time.Second}},
+		{"font square", Command{Verb: VerbFont, Text: "font square", Name: "square"}},
+		{"volume 80", Command{Verb: VerbVolume, Text: "volume 80", Volume: Volume{Level: 80}}},
+		{"volume -10", Command{Verb: VerbVolume, Text: "volume -10", Volume: Volume{Level: -10, Relative: true}}},
+		{"set manual work=45,rest=15 x8", Command{
+			Verb: VerbSet, Text: "set manual work=45,rest=15 x8", Mode: types.ModeManual,
+			Intervals: []time.Duration{45 * time.Second, 15 * time.Second},
+			Labels:    []string{"work", "rest"}, Rounds: 8,
+		}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input, types.ModeAuto)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.input, err)
+			continue
+		}
+		if got.Verb != tt.want.Verb || got.Text != tt.want.Text || got.Duration != tt.want.Duration ||
+			got.Name != tt.want.Name || got.Volume != tt.want.Volume || got.Mode != tt.want.Mode ||
+			!slices.Equal(got.Intervals, tt.want.Intervals) || !slices.Equal(got.Labels, tt.want.Labels) ||
+			got.Rounds != tt.want.Rounds {
+			t.Errorf("%q: got %+v, want %+v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestVerbNames(t *testing.T) {
+	for _, v := range Verbs() {
+		got, ok := lookupVerb(v.String())
+		if !ok || got != v {
+			t.Errorf("%s: expected lookup to round-trip, got %v", v, got)
+		}
+	}
+}
+
+func TestParseChain(t *testing.T) {
+	macros := map[string]string{
+		"warmup":  "set auto 5:00 x1; start",
+		"session": "warmup; mute",
+		"loop":    "next; again",
+		"again":   "loop",
+		"self":    "self",
+		"broken":  "set 5:00; strat",
+		"pause":   "next", // hidden by the command
+	}
+	tests := []struct {
+		input string
+		want  string // the expanded chain, or the error
+		start int    // of the error span
+		end   int
+	}{
+		{"set 5:00; start", "set 5:00; start", 0, 0},
+		{"set 5:00 ;start;", "set 5:00; start", 0, 0},
+		{" ; ", "empty command", 2, 2},
+		{"warmpu", `unknown command: "warmpu" (did you mean "warmup"?)`, 0, 6},
+		{"sesion; next", `unknown command: "sesion" (did you mean "session"?)`, 0, 6},
+		{"warmup", "set auto 5:00 x1; start", 0, 0},
+		{"session; unmute", "set auto 5:00 x1; start; mute; unmute", 0, 0},
+		{"pause", "pause", 0, 0},
+		{"next; nxt", `unknown command: "nxt" (did you mean "next"?)`, 6, 9},
+		{"start; warmup now", "macro warmup takes no arguments", 14, 17},
+		{"mute; loop", `in macro "again": macro "loop" calls itself (loop → again → loop)`, 6, 10},
+		{"self", `in macro "self": macro "self" calls itself (self → self)`, 0, 4},
+		{"next; broken", `in macro "broken": unknown command: "strat" (did you mean "start"?)`, 6, 12},
+	}
+	for _, tt := range tests {
+		chain, err := ParseChain(tt.input, types.ModeAuto, macros)
+		if err == nil {
+			if got := chain.String(); got != tt.want {
+				t.Errorf("%q: expected %q, got %q", tt.input, tt.want, got)
+			}
+			continue
+		}
+		e, ok := err.(*Error)
+		if !ok {
+			t.Errorf("%q: expected *Error, got %v", tt.input, err)
+			continue
+		}
+		if e.Error() != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.input, tt.want, e.Error())
+		}
+		if e.Start != tt.start || e.End != tt.end {
+			t.Errorf("%q: expected span %d-%d, got %d-%d", tt.input, tt.start, tt.end, e.Start, e.End)
+		}
+	}
+}
+
+func TestCheckMacros(t *testing.T) {
+	err := CheckMacros(map[string]string{
+		"warmup":   "set 5:00; start",
+		"next":     "pause",
+		"two word": "start",
+		"loop":     "loop",
+	}, types.ModeAuto)
+	want := `in macro "loop": macro "loop" calls itself (loop → loop); ` +
+		`macro "next" is hidden by the command of the same name; ` +
+		`macro name "two word" must be a single word`
+	if err == nil || err.Error() != want {
+		t.Errorf("expected %q, got %v", want, err)
+	}
+	if err := CheckMacros(map[string]string{"warmup": "set 5:00; start"}, types.ModeAuto); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestCompleteChainsAndMacros(t *testing.T) {
+	words := map[string][]string{"": {"warmup", "session"}}
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"w", []string{"warmup"}},
+		{"se", []string{"session", "set"}},
+		{"set 5:00;st", []string{"set 5:00; start", "set 5:00; status", "set 5:00; stopwatch"}},
+		{"mute; war", []string{"mute; warmup"}},
+	}
+	for _, tt := range tests {
+		if got := Complete(tt.input, words); !slices.Equal(got, tt.want) {
+			t.Errorf("Complete(%q): got %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
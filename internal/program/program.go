@@ -11,8 +11,19 @@ const (
 	ProgramDone
 )
 
+// Interval is a step of a program seen ahead of time.
+type Interval struct {
+	Label    string
+	Duration time.Duration
+}
+
 type Program interface {
 	Tick(elapsed time.Duration) bool
+	// CrossedSecond returns the whole seconds remaining that the last Tick
+	// counted down to, i.e. the value that just appeared on the display, or
+	// 0 if it crossed no whole-second boundary above zero. A delayed tick
+	// that skips several boundaries reports only the last one.
+	CrossedSecond() int
 	Start()
 	TogglePause()
 	Next()
@@ -28,14 +39,39 @@ type Program interface {
 	State() ProgramState
 	// TimeDisplay returns the duration to render (always non-negative)
 	TimeDisplay() time.Duration
+	// TimeDisplayAt is TimeDisplay at a finer resolution than whole
+	// seconds, rounded in the same direction so the whole seconds agree.
+	TimeDisplayAt(resolution time.Duration) time.Duration
 	// IsOverflow reports whether we are past zero in manual mode
 	IsOverflow() bool
 	// IsLowTime determines if the timer (only in countdown mode) is less than the threshhold
 	IsLowTime(threshold time.Duration) bool
+	// IsHalfway reports whether half or less of the current interval is
+	// left to count down.
+	IsHalfway() bool
 	// IntervalProgress returns (current, total) interval for display.
 	// Returns (0, 0) if not applicable (e.g. single interval or stopwatch).
 	IntervalProgress() (current, total int)
 	// RoundProgress returns (current, total) round for display.
 	// Returns (0, 0) if looping forever or not applicable.
 	RoundProgress() (current, total int)
+	// Position returns the zero-based interval and round indices. Unlike the
+	// *Progress methods it is defined for every program and keeps counting
+	// rounds when looping forever, so callers can detect transitions.
+	Position() (interval, round int)
+	// Label returns the current interval's label, e.g. "work" or "rest",
+	// or "" if it has none.
+	Label() string
+	// IntervalLength returns the configured length of the current
+	// interval, or 0 for programs without intervals.
+	IntervalLength() time.Duration
+	// TotalDuration returns the length of the whole program, or 0 if it
+	// has no fixed end.
+	TotalDuration() time.Duration
+	// TotalRemaining returns the time left in the whole program, not
+	// counting overflow, or 0 if it has no fixed end.
+	TotalRemaining() time.Duration
+	// Upcoming returns up to n intervals after the current one, continuing
+	// into later rounds, or nil for programs without intervals.
+	Upcoming(n int) []Interval
 }
@@ -3,7 +3,7 @@ package renderer
 // pixel maps each supported character to its 5-row block representation.
 // Every row is exactly 6 characters wide (right-padded with spaces).
 // Uses Unicode half-block characters (▄ ▀ ▌ ▐) for a bevelled look.
-var pixel = map[rune][5]string{
+var pixel = map[rune][]string{
 	'0': {
 		"▄████▄",
 		"██  ██",
@@ -81,13 +81,20 @@ var pixel = map[rune][5]string{
 		"  ██  ",
 		"      ",
 	},
+	'.': {
+		"    ",
+		"    ",
+		"    ",
+		"    ",
+		" ██ ",
+	},
 }
 
 // powerline maps each supported character to its 5-row block representation.
 // Every row is exactly 6 characters wide (right-padded with spaces).
 // Uses full-block (█) characters with Powerline rounded-corner glyphs
 // (U+E0BA, U+E0B8, U+E0BE, U+E0BC) — requires a Nerd Font / Powerline-patched font.
-var powerline = map[rune][5]string{
+var powerline = map[rune][]string{
 	'0': {
 		"\uE0BA████\uE0B8",
 		"██  ██",
@@ -165,4 +172,118 @@ var powerline = map[rune][5]string{
 		"  ██  ",
 		"      ",
 	},
+	'.': {
+		"    ",
+		"    ",
+		"    ",
+		"    ",
+		" ██ ",
+	},
+}
+
+// square maps each supported character to its 5-row block representation.
+// Every row is exactly 6 characters wide (right-padded with spaces).
+// Uses only full blocks (█) for square corners that render in any font.
+var square = map[rune][]string{
+	'0': {
+		"██████",
+		"██  ██",
+		"██  ██",
+		"██  ██",
+		"██████",
+	},
+	'1': {
+		"████  ",
+		"  ██  ",
+		"  ██  ",
+		"  ██  ",
+		"██████",
+	},
+	'2': {
+		"██████",
+		"    ██",
+		"██████",
+		"██    ",
+		"██████",
+	},
+	'3': {
+		"██████",
+		"    ██",
+		" █████",
+		"    ██",
+		"██████",
+	},
+	'4': {
+		"██  ██",
+		"██  ██",
+		"██████",
+		"    ██",
+		"    ██",
+	},
+	'5': {
+		"██████",
+		"██    ",
+		"██████",
+		"    ██",
+		"██████",
+	},
+	'6': {
+		"██████",
+		"██    ",
+		"██████",
+		"██  ██",
+		"██████",
+	},
+	'7': {
+		"██████",
+		"    ██",
+		"    ██",
+		"    ██",
+		"    ██",
+	},
+	'8': {
+		"██████",
+		"██  ██",
+		"██████",
+		"██  ██",
+		"██████",
+	},
+	'9': {
+		"██████",
+		"██  ██",
+		"██████",
+		"    ██",
+		"██████",
+	},
+	':': {
+		"      ",
+		"  ██  ",
+		"      ",
+		"  ██  ",
+		"      ",
+	},
+	'.': {
+		"    ",
+		"    ",
+		"    ",
+		"    ",
+		" ██ ",
+	},
+}
+
+// small maps each supported character to a 3-row half-block glyph, for
+// panes too short for the 5-row font. Digits are 3 characters wide.
+var small = map[rune][]string{
+	'0': {"█▀█", "█ █", "▀▀▀"},
+	'1': {"▀█ ", " █ ", "▀▀▀"},
+	'2': {"▀▀█", "█▀▀", "▀▀▀"},
+	'3': {"▀▀█", " ▀█", "▀▀▀"},
+	'4': {"█ █", "▀▀█", "  ▀"},
+	'5': {"█▀▀", "▀▀█", "▀▀▀"},
+	'6': {"█▀▀", "█▀█", "▀▀▀"},
+	'7': {"▀▀█", "  █", "  ▀"},
+	'8': {"█▀█", "█▀█", "▀▀▀"},
+	'9': {"█▀█", "▀▀█", "▀▀▀"},
+	':': {"▄", "▄", " "},
+	'.': {" ", " ", "▀"},
 }
@@ -0,0 +1,184 @@
+package renderer
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// required lists the characters every font must draw.
+const required = "0123456789:"
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]*Font{
+		Bevelled.Name:  Bevelled,
+		Square.Name:    Square,
+		Powerline.Name: Powerline,
+	}
+)
+
+// Register makes f available by name, replacing any font of that name.
+func Register(f *Font) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[f.Name] = f
+}
+
+// Lookup returns the registered font with the given name.
+func Lookup(name string) (*Font, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	f, ok := registry[name]
+	return f, ok
+}
+
+// Fonts returns the names of all registered fonts, sorted.
+func Fonts() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseFont reads a font in the format of specs/character-font-reference.txt:
+// each glyph starts with a header line naming its character,
+//
+//	--------- 7 ---------
+//
+// followed by its rows. Empty lines after a glyph are ignored, so a blank
+// bottom row must contain at least one space. Lines before the first header
+// are comments. Rows are right-padded to the glyph's widest row, every
+// glyph must have the same number of rows, and 0-9 and ':' are required.
+// Fonts without a '.' get a square point on their bottom row. Like the
+// built-in fonts, digits are drawn two columns apart and separators with no
+// gap, so ':' and '.' glyphs carry their own padding.
+func ParseFont(name string, r io.Reader) (*Font, error) {
+	glyphs := map[rune][]string{}
+	var order []rune
+	var current rune
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if ch, ok := parseHeader(text); ok {
+			if _, dup := glyphs[ch]; dup {
+				return nil, fmt.Errorf("line %d: duplicate glyph %q", line, ch)
+			}
+			current = ch
+			glyphs[ch] = nil
+			order = append(order, ch)
+			continue
+		}
+		if current != 0 {
+			glyphs[current] = append(glyphs[current], text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(order) == 0 {
+		return nil, errors.New("no glyphs found")
+	}
+
+	height := 0
+	for i, ch := range order {
+		rows := glyphs[ch]
+		for len(rows) > 0 && rows[len(rows)-1] == "" {
+			rows = rows[:len(rows)-1]
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("glyph %q has no rows", ch)
+		}
+		if i == 0 {
+			height = len(rows)
+		} else if len(rows) != height {
+			return nil, fmt.Errorf("glyph %q has %d rows, but %q has %d", ch, len(rows), order[0], height)
+		}
+		glyphs[ch] = padRows(rows)
+	}
+	for _, ch := range required {
+		if _, ok := glyphs[ch]; !ok {
+			return nil, fmt.Errorf("missing glyph %q", ch)
+		}
+	}
+	if _, ok := glyphs['.']; !ok {
+		glyphs['.'] = pointGlyph(height)
+	}
+	return &Font{Name: name, height: height, glyphs: glyphs, digitGap: 2, colonGap: 0}, nil
+}
+
+// pointGlyph draws a decimal point as a block on the bottom row, padded
+// like the built-in colons since fonts put no gap around separators.
+func pointGlyph(height int) []string {
+	rows := make([]string, height)
+	for i := range rows {
+		rows[i] = "    "
+	}
+	rows[height-1] = " ██ "
+	return rows
+}
+
+// LoadFonts registers every *.txt font in dir under its file name without
+// the extension. A missing directory is not an error; fonts that fail to
+// parse are skipped and reported together.
+func LoadFonts(dir string) error {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.txt"))
+	var errs []error
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".txt")
+		f, err := loadFont(name, path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("font %s: %w", name, err))
+			continue
+		}
+		Register(f)
+	}
+	return errors.Join(errs...)
+}
+
+func loadFont(name, path string) (*Font, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseFont(name, file)
+}
+
+// parseHeader recognises "--------- X ---------" and returns X.
+func parseHeader(line string) (rune, bool) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 || !isRule(fields[0]) || !isRule(fields[2]) {
+		return 0, false
+	}
+	ch, size := utf8.DecodeRuneInString(fields[1])
+	if size != len(fields[1]) {
+		return 0, false
+	}
+	return ch, true
+}
+
+func isRule(s string) bool {
+	return len(s) >= 3 && strings.Trim(s, "-") == ""
+}
+
+// padRows right-pads rows with spaces to the width of the widest.
+func padRows(rows []string) []string {
+	width := Width(rows)
+	padded := make([]string, len(rows))
+	for i, row := range rows {
+		padded[i] = row + strings.Repeat(" ", width-utf8.RuneCountInString(row))
+	}
+	return padded
+}
@@ -1,34 +1,56 @@
 package renderer
 
-import "strings"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
-const glyphHeight = 5
-const digitGap = 2 // space between two digit glyphs
-const colonGap = 0 // extra space when either neighbour is a colon (it has built-in padding)
+// Font is a set of equally tall glyphs plus the spacing between them.
+type Font struct {
+	Name     string
+	height   int
+	glyphs   map[rune][]string
+	digitGap int // space between two digit glyphs
+	colonGap int // space when either neighbour is a colon or decimal point
+}
+
+// Height returns the font's height in rows.
+func (f *Font) Height() int { return f.height }
+
+// The 5-row colons and points have built-in padding; the small ones don't.
+var (
+	Bevelled  = &Font{Name: "bevelled", height: 5, glyphs: pixel, digitGap: 2, colonGap: 0}
+	Square    = &Font{Name: "square", height: 5, glyphs: square, digitGap: 2, colonGap: 0}
+	Powerline = &Font{Name: "powerline", height: 5, glyphs: powerline, digitGap: 2, colonGap: 0}
+	smallFont = &Font{Name: "small", height: 3, glyphs: small, digitGap: 1, colonGap: 1}
+)
 
 // gapBefore returns the number of spaces to insert before the current character
 // given the previous character.
-func gapBefore(prev, cur rune) int {
-	if prev == ':' || cur == ':' {
-		return colonGap
+func (f *Font) gapBefore(prev, cur rune) int {
+	if isSeparator(prev) || isSeparator(cur) {
+		return f.colonGap
 	}
-	return digitGap
+	return f.digitGap
 }
 
-// BigDigits renders a string of digits and colons into a slice of 5 strings,
-// one per row. Characters not present in the font are skipped.
-func BigDigits(s string) []string {
-	var rows [glyphHeight]strings.Builder
+func isSeparator(ch rune) bool {
+	return ch == ':' || ch == '.'
+}
+
+// render lays out s in f, one string per row. Characters not present in
+// the font are skipped.
+func (f *Font) render(s string) []string {
+	rows := make([]strings.Builder, f.height)
 
 	prev := rune(0)
 	for _, ch := range s {
-		// TODO: make font type a config setting
-		glyph, ok := pixel[ch]
+		glyph, ok := f.glyphs[ch]
 		if !ok {
 			continue
 		}
 		if prev != 0 {
-			gap := gapBefore(prev, ch)
+			gap := f.gapBefore(prev, ch)
 			for r := range rows {
 				rows[r].WriteString(strings.Repeat(" ", gap))
 			}
@@ -39,9 +61,135 @@ func BigDigits(s string) []string {
 		}
 	}
 
-	result := make([]string, glyphHeight)
+	result := make([]string, f.height)
 	for r, b := range rows {
 		result[r] = b.String()
 	}
 	return result
 }
+
+// BigDigits renders a string of digits, colons and points into a slice of 5 strings,
+// one per row, in the bevelled font. Characters not present in the font
+// are skipped.
+func BigDigits(s string) []string {
+	return Render(s, Bevelled, Rows5)
+}
+
+// Size is a big-digit size, named by its height in rows.
+type Size int
+
+// The sizes available to a 5-row font.
+const (
+	Rows1  Size = 1  // the plain text
+	Rows3  Size = 3  // the small font
+	Rows5  Size = 5  // the font itself
+	Rows10 Size = 10 // the font at double scale
+	Rows15 Size = 15 // the font at triple scale
+)
+
+// Sizes lists the sizes f can be drawn at, largest first: the font scaled
+// 3× and 2×, as designed, then the small font and plain text.
+func (f *Font) Sizes() []Size {
+	h := Size(f.height)
+	sizes := []Size{3 * h, 2 * h, h}
+	if h > Rows3 {
+		sizes = append(sizes, Rows3)
+	}
+	if h > Rows1 {
+		sizes = append(sizes, Rows1)
+	}
+	return sizes
+}
+
+// Render draws s in f at the given size, one string per row. Multiples of
+// the font's height scale it; below its height, 3 rows is the small font
+// and 1 row plain text. Other sizes fall back to its own height.
+func Render(s string, f *Font, size Size) []string {
+	h := Size(f.height)
+	switch {
+	case size > h && size%h == 0:
+		return scale(f.render(s), int(size/h))
+	case size == Rows3 && h > Rows3:
+		return smallFont.render(s)
+	case size == Rows1 && h > Rows1:
+		return []string{s}
+	}
+	return f.render(s)
+}
+
+// Fit returns the largest size at which s, drawn in f, fits in width
+// columns and height rows, and the rendered rows. When nothing fits, s is
+// returned as plain text and ok is false.
+func Fit(s string, f *Font, width, height int) (rows []string, size Size, ok bool) {
+	for _, size := range f.Sizes() {
+		rows := Render(s, f, size)
+		if len(rows) <= height && Width(rows) <= width {
+			return rows, size, true
+		}
+	}
+	return []string{s}, Rows1, false
+}
+
+// Width returns the width of rendered rows in terminal columns.
+func Width(rows []string) int {
+	w := 0
+	for _, row := range rows {
+		w = max(w, utf8.RuneCountInString(row))
+	}
+	return w
+}
+
+// quadrants maps a 4-bit set of filled quarters (top-left 1, top-right 2,
+// bottom-left 4, bottom-right 8) to the block character drawing them.
+var quadrants = []rune(" ▘▝▀▖▌▞▛▗▚▐▜▄▙▟█")
+
+// quadrantBits is the inverse of quadrants.
+var quadrantBits = func() map[rune]int {
+	bits := make(map[rune]int, len(quadrants))
+	for i, r := range quadrants {
+		bits[r] = i
+	}
+	return bits
+}()
+
+// scale enlarges block-character rows by an integer factor, treating each
+// character as a 2×2 grid of quarters so half blocks keep their shape.
+// Characters that aren't block elements are drawn as full blocks.
+func scale(rows []string, factor int) []string {
+	src := make([][]rune, len(rows))
+	for i, row := range rows {
+		src[i] = []rune(row)
+	}
+	filled := func(fy, fx int) bool {
+		y, x := fy/2, fx/2
+		if y >= len(src) || x >= len(src[y]) {
+			return false
+		}
+		bits, ok := quadrantBits[src[y][x]]
+		if !ok {
+			bits = 15
+		}
+		return bits&(1<<((fy%2)*2+fx%2)) != 0
+	}
+
+	out := make([]string, len(rows)*factor)
+	for oy := range out {
+		width := 0
+		if y := oy / factor; y < len(src) {
+			width = len(src[y]) * factor
+		}
+		var b strings.Builder
+		for ox := 0; ox < width; ox++ {
+			bits := 0
+			for q := 0; q < 4; q++ {
+				fy, fx := oy*2+q/2, ox*2+q%2
+				if filled(fy/factor, fx/factor) {
+					bits |= 1 << q
+				}
+			}
+			b.WriteRune(quadrants[bits])
+		}
+		out[oy] = b.String()
+	}
+	return out
+}
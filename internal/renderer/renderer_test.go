@@ -0,0 +1,202 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRenderHeights(t *testing.T) {
+	for _, f := range []*Font{Bevelled, Square, Powerline} {
+		for _, size := range f.Sizes() {
+			rows := Render("12:34.56", f, size)
+			if len(rows) != int(size) {
+				t.Errorf("%s size %d: expected %d rows, got %d", f.Name, size, size, len(rows))
+			}
+			w := Width(rows)
+			for i, row := range rows {
+				if got := Width([]string{row}); got != w {
+					t.Errorf("%s size %d row %d: expected width %d, got %d", f.Name, size, i, w, got)
+				}
+			}
+		}
+	}
+}
+
+func TestPointIsDrawn(t *testing.T) {
+	for _, size := range Bevelled.Sizes()[:4] {
+		with := Width(Render("0:05.3", Bevelled, size))
+		without := Width(Render("0:053", Bevelled, size))
+		if with <= without {
+			t.Errorf("size %d: expected the point to add width, got %d and %d", size, with, without)
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	w5 := Width(Render("1:30", Bevelled, Rows5))
+	w15 := Width(Render("1:30", Bevelled, Rows15))
+
+	tests := []struct {
+		name          string
+		width, height int
+		want          Size
+		wantOK        bool
+	}{
+		{"full screen", 200, 50, Rows15, true},
+		{"exactly 15 rows", w15, 15, Rows15, true},
+		{"one column short of 15", w15 - 1, 50, Rows10, true},
+		{"tmux pane", w5, 8, Rows5, true},
+		{"short pane", 80, 4, Rows3, true},
+		{"one line", 80, 1, Rows1, true},
+		{"too narrow for anything", 3, 50, Rows1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, size, ok := Fit("1:30", Bevelled, tt.width, tt.height)
+			if size != tt.want || ok != tt.wantOK {
+				t.Errorf("expected %d (%v), got %d (%v)", tt.want, tt.wantOK, size, ok)
+			}
+		})
+	}
+}
+
+func TestScale(t *testing.T) {
+	got := scale([]string{"▄▀", "▌█"}, 2)
+	want := []string{
+		"  ██",
+		"██  ",
+		"█ ██",
+		"█ ██",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d rows, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d: expected %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	// At triple scale a half block covers one and a half cells.
+	if got := scale([]string{"▀"}, 3); got[1] != "▀▀▀" {
+		t.Errorf("expected the middle row to be half filled, got %q", got[1])
+	}
+}
+
+// sevenRowFont is a valid user font: every glyph is a 7-row bar, except
+// the colon, whose blank rows are kept by their spaces.
+func sevenRowFont() string {
+	var b strings.Builder
+	b.WriteString("A comment before the first glyph.\n\n")
+	for _, ch := range "0123456789" {
+		b.WriteString("--------- " + string(ch) + " ---------\n")
+		b.WriteString(strings.Repeat("██\n", 7) + "\n")
+	}
+	b.WriteString("--------- : ---------\n \n█\n \n \n█\n \n \n")
+	return b.String()
+}
+
+func TestParseFont(t *testing.T) {
+	f, err := ParseFont("tall", strings.NewReader(sevenRowFont()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.Height() != 7 {
+		t.Errorf("expected 7 rows, got %d", f.Height())
+	}
+	want := []Size{21, 14, 7, 3, 1}
+	if got := f.Sizes(); !slices.Equal(got, want) {
+		t.Errorf("expected sizes %v, got %v", want, got)
+	}
+	if rows := Render("1:0", f, 14); len(rows) != 14 {
+		t.Errorf("expected 14 rows at double scale, got %d", len(rows))
+	}
+	if point := Render(".", f, 7); len(point) != 7 || strings.TrimSpace(point[6]) == "" {
+		t.Errorf("expected a point on the bottom row, got %q", point)
+	}
+
+	bad := []struct {
+		name, input, wantErr string
+	}{
+		{"inconsistent", strings.Replace(sevenRowFont(), "██\n\n---", "\n---", 1), "glyph '1' has 7 rows, but '0' has 6"},
+		{"missing", strings.Split(sevenRowFont(), "--------- : ")[0], "missing glyph ':'"},
+		{"duplicate", sevenRowFont() + "--------- 1 ---------\nx\n", "duplicate glyph '1'"},
+		{"empty", "just a comment\n", "no glyphs"},
+	}
+	for _, tt := range bad {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFont("bad", strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestShortFontsScale(t *testing.T) {
+	var b strings.Builder
+	for _, ch := range "0123456789:" {
+		b.WriteString("--------- " + string(ch) + " ---------\n" + string(ch) + "\n\n")
+	}
+	tiny, err := ParseFont("tiny", strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, size := range tiny.Sizes() {
+		rows := Render("1:30", tiny, size)
+		if len(rows) != int(size) {
+			t.Errorf("size %d: expected %d rows, got %d", size, size, len(rows))
+		}
+		// Digits are digitGap apart; colons bring their own padding.
+		if want := "1:3  0"; size == Rows1 && rows[0] != want {
+			t.Errorf("size 1: expected the font's own glyphs %q, got %q", want, rows[0])
+		}
+	}
+	if rows := Render("1:30", tiny, Rows3); strings.Contains(strings.Join(rows, ""), "▀") {
+		t.Errorf("expected 3 rows to scale the font rather than use the small font, got %q", rows)
+	}
+}
+
+func TestLoadFonts(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "tall.txt"), []byte(sevenRowFont()), 0o644)
+	os.WriteFile(filepath.Join(dir, "broken.txt"), []byte("--------- 1 ---------\n█\n"), 0o644)
+
+	err := LoadFonts(dir)
+	if err == nil || !strings.Contains(err.Error(), "font broken") {
+		t.Errorf("expected an error for the broken font, got %v", err)
+	}
+	if f, ok := Lookup("tall"); !ok || f.Height() != 7 {
+		t.Error("expected tall to be registered")
+	}
+	if _, ok := Lookup("broken"); ok {
+		t.Error("expected broken not to be registered")
+	}
+	if names := Fonts(); !slices.Contains(names, "square") || !slices.Contains(names, "tall") {
+		t.Errorf("expected built-in and loaded fonts, got %v", names)
+	}
+	if err := LoadFonts(filepath.Join(dir, "missing")); err != nil {
+		t.Errorf("expected a missing directory to be ignored, got %v", err)
+	}
+}
+
+func TestReferenceFonts(t *testing.T) {
+	paths, _ := filepath.Glob("../../specs/character-font-*.txt")
+	if len(paths) == 0 {
+		t.Fatal("expected reference fonts in specs")
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			f, err := loadFont("reference", path)
+			if err != nil {
+				t.Fatalf("expected the reference font to load, got %v", err)
+			}
+			if f.Height() != 5 {
+				t.Errorf("expected 5 rows, got %d", f.Height())
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package server
+
+import (
+	"sync"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+)
+
+// hub remembers the latest snapshot and fans it out to subscribers. It is
+// embedded by each listener so they all share the same Publish behaviour.
+type hub struct {
+	mu   sync.Mutex
+	last model.Snapshot
+	subs map[chan model.Snapshot]struct{}
+}
+
+// Publish records the latest snapshot and forwards it to every subscriber.
+// Unchanged snapshots are dropped, and a slow subscriber misses updates
+// rather than blocking the caller.
+func (h *hub) Publish(snap model.Snapshot) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if snap == h.last {
+		return
+	}
+	h.last = snap
+	for ch := range h.subs {
+		select {
+		case ch <- snap:
+		default:
+		}
+	}
+}
+
+func (h *hub) snapshot() model.Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.last
+}
+
+func (h *hub) subscribe() chan model.Snapshot {
+	ch := make(chan model.Snapshot, 1)
+	h.mu.Lock()
+	if h.subs == nil {
+		h.subs = make(map[chan model.Snapshot]struct{})
+	}
+	h.subs[ch] = struct{}{}
+	h.mu.Unlock()
+	return ch
+}
+
+func (h *hub) unsubscribe(ch chan model.Snapshot) {
+	h.mu.Lock()
+	delete(h.subs, ch)
+	h.mu.Unlock()
+}
@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+//go:embed dashboard.html
+var dashboard []byte
+
+// Server exposes the timer over HTTP on localhost: REST endpoints that feed
+// the same command dispatch as the prompt, a Server-Sent-Events state
+// stream, and a single-page dashboard.
+type Server struct {
+	hub
+	dispatch    func(commands parser.Chain)
+	defaultMode types.Mode
+	macros      map[string]string
+	port        int // the Host and Origin requests must name
+	http        *http.Server
+}
+
+// New returns a Server that will listen on 127.0.0.1:port. Valid commands
+// are passed to dispatch, which must be safe to call from any goroutine
+// (e.g. a wrapper around tea.Program.Send).
+func New(port int, defaultMode types.Mode, macros map[string]string, dispatch func(commands parser.Chain)) *Server {
+	s := &Server{
+		dispatch:    dispatch,
+		defaultMode: defaultMode,
+		macros:      macros,
+		port:        port,
+	}
+	s.http = &http.Server{
+		Addr:    fmt.Sprintf("127.0.0.1:%d", port),
+		Handler: s.Handler(),
+	}
+	return s
+}
+
+// Handler returns the HTTP routes without binding a listener.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleDashboard)
+	mux.HandleFunc("GET /api/state", s.handleState)
+	mux.HandleFunc("GET /api/events", s.handleEvents)
+	mux.HandleFunc("POST /api/command", s.handleCommand)
+	mux.HandleFunc("POST /api/{verb}", s.handleVerb)
+	return s.guard(mux)
+}
+
+// guard keeps other web pages out: a page the user visits can send
+// requests to 127.0.0.1 too. Requests must name this server in Host,
+// which DNS rebinding can't fake, and must not come from another origin;
+// POSTs must be JSON, which browsers won't send cross-site without a
+// CORS preflight that is never granted.
+func (s *Server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.local(r.Host) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" && !s.local(strings.TrimPrefix(origin, "http://")) {
+			http.Error(w, "forbidden origin", http.StatusForbidden)
+			return
+		}
+		if r.Method == http.MethodPost && !isJSON(r) {
+			http.Error(w, "expected Content-Type application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// local reports whether hostport is 127.0.0.1 or localhost on our port.
+func (s *Server) local(hostport string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	return err == nil && (host == "127.0.0.1" || host == "localhost") && port == strconv.Itoa(s.port)
+}
+
+func isJSON(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// Start binds the listener and serves in a goroutine. Errors binding the
+// port are returned; errors after that are dropped.
+func (s *Server) Start() error {
+	ln, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	s.port = ln.Addr().(*net.TCPAddr).Port
+	go s.http.Serve(ln)
+	return nil
+}
+
+// Close stops the listener and disconnects event streams.
+func (s *Server) Close() error {
+	return s.http.Shutdown(context.Background())
+}
+
+func (s *Server) handleDashboard(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(dashboard)
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(s.snapshot())
+}
+
+func (s *Server) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	writeEvent(w, s.snapshot())
+	flusher.Flush()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case snap := <-ch:
+			writeEvent(w, snap)
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, snap model.Snapshot) {
+	data, _ := json.Marshal(snap)
+	fmt.Fprintf(w, "data: %s\n\n", data)
+}
+
+// handleCommand accepts a full command line as a JSON object
+// {"command": "..."}.
+func (s *Server) handleCommand(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Command string `json:"command"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	chain, err := parser.ParseChain(req.Command, s.defaultMode, s.macros)
+	s.run(w, chain, err)
+}
+
+// handleVerb maps POST /api/<verb> to the command "<verb> <args>", where
+// args comes from an optional JSON body (e.g. POST /api/add with body
+// {"args": "30"}). It runs exactly that one command, never a chain or a
+// macro.
+func (s *Server) handleVerb(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Args string `json:"args"`
+	}
+	if !decode(w, r, &req) {
+		return
+	}
+	command, err := parser.Parse(strings.TrimSpace(r.PathValue("verb")+" "+req.Args), s.defaultMode)
+	s.run(w, parser.Chain{command}, err)
+}
+
+// decode reads a JSON request body into v, where an empty body leaves v
+// as it is. It reports false after replying to a bad body.
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	body, err := io.ReadAll(io.LimitReader(r.Body, 1024))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+	if len(strings.TrimSpace(string(body))) > 0 && json.Unmarshal(body, v) != nil {
+		http.Error(w, "invalid JSON body", http.StatusBadRequest)
+		return false
+	}
+	return true
+}
+
+// run dispatches a parsed chain, or replies with the error parsing it.
+func (s *Server) run(w http.ResponseWriter, chain parser.Chain, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.dispatch(chain)
+	w.WriteHeader(http.StatusAccepted)
+}
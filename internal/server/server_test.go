@@ -0,0 +1,197 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+func newTestServer() (*Server, *[]string) {
+	var got []string
+	s := New(0, types.ModeAuto, nil, func(command parser.Chain) {
+		got = append(got, command.String())
+	})
+	return s, &got
+}
+
+// request returns a request as the dashboard sends it: to this server by
+// name, with a JSON body for POSTs.
+func request(method, path, body string) *http.Request {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Host = "127.0.0.1:0"
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req
+}
+
+func TestVerbEndpointsDispatch(t *testing.T) {
+	tests := []struct {
+		path string
+		body string
+		want string
+	}{
+		{"/api/next", "", "next"},
+		{"/api/pause", "", "pause"},
+		{"/api/add", `{"args": "30"}`, "add 30"},
+		{"/api/set", `{"args": "auto 1:30,60 x3"}`, "set auto 1:30,60 x3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			s, got := newTestServer()
+			rec := httptest.NewRecorder()
+			s.Handler().ServeHTTP(rec, request("POST", tt.path, tt.body))
+
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("expected 202, got %d: %s", rec.Code, rec.Body)
+			}
+			if len(*got) != 1 || (*got)[0] != tt.want {
+				t.Errorf("expected dispatch %q, got %q", tt.want, *got)
+			}
+		})
+	}
+}
+
+func TestCommandEndpoint(t *testing.T) {
+	s, got := newTestServer()
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, request("POST", "/api/command", `{"command": "subtract 1:00"}`))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected 202, got %d", rec.Code)
+	}
+	if len(*got) != 1 || (*got)[0] != "subtract 1:00" {
+		t.Errorf("expected dispatch of subtract 1:00, got %q", *got)
+	}
+}
+
+func TestInvalidCommandIsRejected(t *testing.T) {
+	for _, path := range []string{"/api/fly", "/api/add"} {
+		s, got := newTestServer()
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, request("POST", path, ""))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", path, rec.Code)
+		}
+		if len(*got) != 0 {
+			t.Errorf("%s: invalid command should not dispatch, got %q", path, *got)
+		}
+	}
+}
+
+func TestVerbEndpointsRunOneCommand(t *testing.T) {
+	tests := []struct{ path, body string }{
+		{"/api/next", `{"args": "; quit"}`},
+		{"/api/add", `{"args": "30; quit"}`},
+		{"/api/warmup", ""},
+	}
+	for _, tt := range tests {
+		s, got := newTestServer()
+		s.macros = map[string]string{"warmup": "set auto 5:00; start"}
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, request("POST", tt.path, tt.body))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s %s: expected 400, got %d", tt.path, tt.body, rec.Code)
+		}
+		if len(*got) != 0 {
+			t.Errorf("%s %s: expected no dispatch, got %q", tt.path, tt.body, *got)
+		}
+	}
+}
+
+func TestStateReturnsLatestSnapshot(t *testing.T) {
+	s, _ := newTestServer()
+	s.Publish(model.Snapshot{State: "running", Time: "1:30"})
+
+	rec := httptest.NewRecorder()
+	s.Handler().ServeHTTP(rec, request("GET", "/api/state", ""))
+
+	var snap model.Snapshot
+	if err := json.NewDecoder(rec.Body).Decode(&snap); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if snap.State != "running" || snap.Time != "1:30" {
+		t.Errorf("unexpected snapshot %+v", snap)
+	}
+}
+
+func TestEventsStreamPublishedSnapshots(t *testing.T) {
+	s, _ := newTestServer()
+	ts := httptest.NewServer(s.Handler())
+	defer ts.Close()
+	s.port = ts.Listener.Addr().(*net.TCPAddr).Port
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, "GET", ts.URL+"/api/events", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	lines := bufio.NewScanner(resp.Body)
+	// The first event is the current (empty) snapshot.
+	if !lines.Scan() || !strings.HasPrefix(lines.Text(), "data: ") {
+		t.Fatalf("expected initial event, got %q", lines.Text())
+	}
+
+	s.Publish(model.Snapshot{State: "paused", Time: "0:42"})
+	for lines.Scan() {
+		line := lines.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		var snap model.Snapshot
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &snap); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if snap.State != "paused" || snap.Time != "0:42" {
+			t.Errorf("unexpected snapshot %+v", snap)
+		}
+		return
+	}
+	t.Fatal("stream ended before published snapshot arrived")
+}
+
+func TestForeignRequestsAreRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*http.Request)
+		want   int
+	}{
+		{"other host", func(r *http.Request) { r.Host = "evil.example:0" }, http.StatusForbidden},
+		{"other port", func(r *http.Request) { r.Host = "127.0.0.1:8080" }, http.StatusForbidden},
+		{"other origin", func(r *http.Request) { r.Header.Set("Origin", "https://evil.example") }, http.StatusForbidden},
+		{"plain text", func(r *http.Request) { r.Header.Set("Content-Type", "text/plain") }, http.StatusUnsupportedMediaType},
+		{"no content type", func(r *http.Request) { r.Header.Del("Content-Type") }, http.StatusUnsupportedMediaType},
+		{"own origin", func(r *http.Request) { r.Header.Set("Origin", "http://localhost:0") }, http.StatusAccepted},
+	}
+	for _, tt := range tests {
+		s, got := newTestServer()
+		req := request("POST", "/api/command", `{"command": "quit"}`)
+		tt.modify(req)
+		rec := httptest.NewRecorder()
+		s.Handler().ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, rec.Code)
+		}
+		if dispatched := len(*got) > 0; dispatched != (tt.want == http.StatusAccepted) {
+			t.Errorf("%s: unexpected dispatch %q", tt.name, *got)
+		}
+	}
+}
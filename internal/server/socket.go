@@ -0,0 +1,190 @@
+package server
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+// Socket is a line-based control socket for other processes on the same
+// machine (the `timer status` / `timer watch` subcommands, scripts).
+//
+// Each line a client sends is one request:
+//
+//	status      → one JSON snapshot line
+//	watch       → a JSON snapshot line on every change until disconnect
+//	<command>   → "ok" once dispatched, or "error: <reason>"
+type Socket struct {
+	hub
+	path        string
+	dispatch    func(commands parser.Chain)
+	defaultMode types.Mode
+	macros      map[string]string
+	ln          net.Listener
+}
+
+// NewSocket returns a Socket that will listen on the Unix socket at path.
+// dispatch has the same contract as for New.
+func NewSocket(path string, defaultMode types.Mode, macros map[string]string, dispatch func(commands parser.Chain)) *Socket {
+	return &Socket{
+		path:        path,
+		dispatch:    dispatch,
+		defaultMode: defaultMode,
+		macros:      macros,
+	}
+}
+
+// Start binds the socket and accepts connections in a goroutine. A stale
+// socket file left by a crashed instance is replaced; a live one means
+// another instance is running and is reported as an error.
+func (s *Socket) Start() error {
+	if conn, err := net.Dial("unix", s.path); err == nil {
+		conn.Close()
+		return fmt.Errorf("another instance is already listening on %s", s.path)
+	}
+	os.Remove(s.path)
+
+	ln, err := net.Listen("unix", s.path)
+	if err != nil {
+		return err
+	}
+	s.ln = ln
+	go s.accept()
+	return nil
+}
+
+// Close stops accepting connections and removes the socket file.
+func (s *Socket) Close() error {
+	if s.ln == nil {
+		return nil
+	}
+	return s.ln.Close()
+}
+
+func (s *Socket) accept() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.serve(conn)
+	}
+}
+
+func (s *Socket) serve(conn net.Conn) {
+	defer conn.Close()
+	lines := bufio.NewScanner(conn)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		switch line {
+		case "":
+			continue
+		case "status":
+			writeSnapshot(conn, s.snapshot())
+		case "watch":
+			s.watch(conn)
+			return
+		default:
+			chain, err := parser.ParseChain(line, s.defaultMode, s.macros)
+			if err != nil {
+				fmt.Fprintf(conn, "error: %v\n", err)
+				continue
+			}
+			s.dispatch(chain)
+			fmt.Fprintln(conn, "ok")
+		}
+	}
+}
+
+// watch streams snapshots until the client goes away.
+func (s *Socket) watch(conn net.Conn) {
+	ch := s.subscribe()
+	defer s.unsubscribe(ch)
+
+	// Reading only returns once the client closes its end.
+	closed := make(chan struct{})
+	go func() {
+		io.Copy(io.Discard, conn)
+		close(closed)
+	}()
+
+	if writeSnapshot(conn, s.snapshot()) != nil {
+		return
+	}
+	for {
+		select {
+		case <-closed:
+			return
+		case snap := <-ch:
+			if writeSnapshot(conn, snap) != nil {
+				return
+			}
+		}
+	}
+}
+
+func writeSnapshot(w io.Writer, snap model.Snapshot) error {
+	data, _ := json.Marshal(snap)
+	_, err := fmt.Fprintf(w, "%s\n", data)
+	return err
+}
+
+// ErrNotRunning is returned by the client helpers when no instance is
+// listening on the socket.
+var ErrNotRunning = errors.New("no running timer instance")
+
+func dial(path string) (net.Conn, error) {
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	return conn, nil
+}
+
+// Status asks the instance listening on path for its current snapshot.
+func Status(path string) (model.Snapshot, error) {
+	var snap model.Snapshot
+	conn, err := dial(path)
+	if err != nil {
+		return snap, err
+	}
+	defer conn.Close()
+
+	fmt.Fprintln(conn, "status")
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return snap, err
+	}
+	err = json.Unmarshal(line, &snap)
+	return snap, err
+}
+
+// Watch calls f with every snapshot the instance listening on path
+// publishes, until the connection drops.
+func Watch(path string, f func(model.Snapshot)) error {
+	conn, err := dial(path)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	fmt.Fprintln(conn, "watch")
+	lines := bufio.NewScanner(conn)
+	for lines.Scan() {
+		var snap model.Snapshot
+		if err := json.Unmarshal(lines.Bytes(), &snap); err != nil {
+			return err
+		}
+		f(snap)
+	}
+	return lines.Err()
+}
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BobbyGerace/workout-timer/internal/model"
+	"github.com/BobbyGerace/workout-timer/internal/parser"
+	"github.com/BobbyGerace/workout-timer/internal/types"
+)
+
+func startSocket(t *testing.T) (*Socket, chan string) {
+	t.Helper()
+	got := make(chan string, 10)
+	s := NewSocket(filepath.Join(t.TempDir(), "timer.sock"), types.ModeAuto, nil, func(command parser.Chain) {
+		got <- command.String()
+	})
+	if err := s.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s, got
+}
+
+func TestSocketStatus(t *testing.T) {
+	s, _ := startSocket(t)
+	s.Publish(model.Snapshot{State: "running", Time: "0:25", LowTime: true})
+
+	snap, err := Status(s.path)
+	if err != nil {
+		t.Fatalf("status: %v", err)
+	}
+	if snap.State != "running" || snap.Time != "0:25" || !snap.LowTime {
+		t.Errorf("unexpected snapshot %+v", snap)
+	}
+}
+
+func TestSocketCommands(t *testing.T) {
+	s, got := startSocket(t)
+	conn, err := net.Dial("unix", s.path)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	replies := bufio.NewScanner(conn)
+
+	conn.Write([]byte("add 30\n"))
+	if !replies.Scan() || replies.Text() != "ok" {
+		t.Errorf("expected ok, got %q", replies.Text())
+	}
+	if cmd := <-got; cmd != "add 30" {
+		t.Errorf("expected dispatch of add 30, got %q", cmd)
+	}
+
+	conn.Write([]byte("fly\n"))
+	if !replies.Scan() || replies.Text() != `error: unknown command: "fly"` {
+		t.Errorf("expected error reply, got %q", replies.Text())
+	}
+}
+
+func TestSocketWatch(t *testing.T) {
+	s, _ := startSocket(t)
+	snaps := make(chan model.Snapshot, 10)
+	go Watch(s.path, func(snap model.Snapshot) { snaps <- snap })
+
+	// The first snapshot is the current one; wait for it so the watcher is
+	// subscribed before publishing.
+	<-snaps
+	s.Publish(model.Snapshot{State: "paused", Time: "1:00"})
+
+	select {
+	case snap := <-snaps:
+		if snap.State != "paused" {
+			t.Errorf("unexpected snapshot %+v", snap)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for published snapshot")
+	}
+}
+
+func TestSocketRefusesSecondInstance(t *testing.T) {
+	s, _ := startSocket(t)
+	other := NewSocket(s.path, types.ModeAuto, nil, func(parser.Chain) {})
+	if err := other.Start(); err == nil {
+		other.Close()
+		t.Error("expected error starting a second listener on the same path")
+	}
+}
+
+func TestStatusWithoutInstance(t *testing.T) {
+	_, err := Status(filepath.Join(t.TempDir(), "missing.sock"))
+	if err != ErrNotRunning {
+		t.Errorf("expected ErrNotRunning, got %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package stopwatch
 
 import (
-	"math"
 	"time"
 
 	"github.com/BobbyGerace/workout-timer/internal/program"
@@ -51,6 +50,10 @@ func (s *Stopwatch) Tick(elapsed time.Duration) bool {
 	return false
 }
 
+func (s *Stopwatch) CrossedSecond() int {
+	return 0
+}
+
 func (s *Stopwatch) Lap() {
 	s.laps = append(s.laps, s.elapsed)
 	s.elapsed = 0
@@ -61,7 +64,11 @@ func (s *Stopwatch) Laps() []time.Duration {
 }
 
 func (s *Stopwatch) TimeDisplay() time.Duration {
-	return time.Duration(math.Floor(s.elapsed.Seconds())) * time.Second
+	return s.TimeDisplayAt(time.Second)
+}
+
+func (s *Stopwatch) TimeDisplayAt(resolution time.Duration) time.Duration {
+	return s.elapsed.Truncate(resolution)
 }
 
 func (s *Stopwatch) IsOverflow() bool {
@@ -72,6 +79,10 @@ func (s *Stopwatch) IsLowTime(threshold time.Duration) bool {
 	return false
 }
 
+func (s *Stopwatch) IsHalfway() bool {
+	return false
+}
+
 func (s *Stopwatch) Back()                    {}
 func (s *Stopwatch) Add(d time.Duration)      {}
 func (s *Stopwatch) Subtract(d time.Duration) {}
@@ -84,6 +95,15 @@ func (s *Stopwatch) Reset() {
 func (s *Stopwatch) IntervalProgress() (current, total int) { return 0, 0 }
 func (s *Stopwatch) RoundProgress() (current, total int)    { return 0, 0 }
 
+func (s *Stopwatch) Label() string                     { return "" }
+func (s *Stopwatch) IntervalLength() time.Duration     { return 0 }
+func (s *Stopwatch) TotalDuration() time.Duration      { return 0 }
+func (s *Stopwatch) TotalRemaining() time.Duration     { return 0 }
+func (s *Stopwatch) Upcoming(n int) []program.Interval { return nil }
+
+// Position treats each lap as an interval.
+func (s *Stopwatch) Position() (interval, round int) { return len(s.laps), 0 }
+
 func (s *Stopwatch) State() program.ProgramState {
 	switch s.state {
 	case StopwatchRunning:
@@ -8,6 +8,9 @@ import (
 	"github.com/BobbyGerace/workout-timer/internal/types"
 )
 
+// ensure *Timer satisfies program.Program at compile time
+var _ program.Program = (*Timer)(nil)
+
 type TimerState int
 
 const (
@@ -19,12 +22,14 @@ const (
 
 type Timer struct {
 	intervals       []time.Duration
-	rounds          int // 0 = loop forever
+	labels          []string // per interval, nil when none are labelled
+	rounds          int      // 0 = loop forever
 	mode            types.Mode
 	currentInterval int
 	currentRound    int
 	timeLeft        time.Duration // can be negative in manual mode
 	state           TimerState
+	crossedSecond   int // set by Tick, see CrossedSecond
 }
 
 func New(intervals []time.Duration, rounds int, mode types.Mode) *Timer {
@@ -37,6 +42,13 @@ func New(intervals []time.Duration, rounds int, mode types.Mode) *Timer {
 	}
 }
 
+// WithLabels names the intervals, e.g. "work" and "rest"; labels[i] belongs
+// to intervals[i]. It returns t so it can follow New.
+func (t *Timer) WithLabels(labels []string) *Timer {
+	t.labels = labels
+	return t
+}
+
 func (t *Timer) Start() {
 	if t.state == TimerReady {
 		t.state = TimerRunning
@@ -54,18 +66,30 @@ func (t *Timer) TogglePause() {
 }
 
 func (t *Timer) Tick(elapsed time.Duration) bool {
+	t.crossedSecond = 0
 	if t.state != TimerRunning {
 		return false
 	}
 	prev := t.timeLeft
 	t.timeLeft -= elapsed
 	crossedZero := prev > 0 && t.timeLeft <= 0
+	if t.timeLeft > 0 && ceilSeconds(t.timeLeft) < ceilSeconds(prev) {
+		t.crossedSecond = ceilSeconds(t.timeLeft)
+	}
 	if (t.mode == types.ModeAuto || t.isFinalInterval()) && t.timeLeft <= 0 {
 		t.Next()
 	}
 	return crossedZero
 }
 
+func (t *Timer) CrossedSecond() int {
+	return t.crossedSecond
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
+
 // Next advances to the next interval, or to the next round if
 // the current interval is the last one. If the last interval of the last
 // round is complete, it transitions to TimerDone. In manual mode this is
@@ -148,10 +172,20 @@ func (t *Timer) Subtract(d time.Duration) {
 
 // TimeDisplay returns the duration to render — always non-negative.
 func (t *Timer) TimeDisplay() time.Duration {
+	return t.TimeDisplayAt(time.Second)
+}
+
+// TimeDisplayAt rounds the time left up, so the display reaches zero just
+// as the interval ends, and overflow down, so it counts up from zero.
+func (t *Timer) TimeDisplayAt(resolution time.Duration) time.Duration {
 	if t.timeLeft < 0 {
-		return time.Duration(math.Floor(-t.timeLeft.Seconds())) * time.Second
+		return (-t.timeLeft).Truncate(resolution)
+	}
+	d := t.timeLeft.Truncate(resolution)
+	if d < t.timeLeft {
+		d += resolution
 	}
-	return time.Duration(math.Ceil(t.timeLeft.Seconds())) * time.Second
+	return d
 }
 
 func (t *Timer) IsOverflow() bool {
@@ -162,6 +196,10 @@ func (t *Timer) IsLowTime(threshold time.Duration) bool {
 	return t.timeLeft > 0 && t.timeLeft < threshold
 }
 
+func (t *Timer) IsHalfway() bool {
+	return t.timeLeft > 0 && t.timeLeft*2 <= t.intervals[t.currentInterval]
+}
+
 func (t *Timer) State() program.ProgramState {
 	switch t.state {
 	case TimerRunning:
@@ -194,6 +232,76 @@ func (t *Timer) RoundProgress() (current, total int) {
 	return t.currentRound + 1, t.rounds
 }
 
+func (t *Timer) Position() (interval, round int) {
+	return t.currentInterval, t.currentRound
+}
+
+func (t *Timer) Label() string {
+	if t.labels == nil {
+		return ""
+	}
+	return t.labels[t.currentInterval]
+}
+
+// IntervalLength returns the configured length of the current interval,
+// regardless of any time added or subtracted.
+func (t *Timer) IntervalLength() time.Duration {
+	return t.intervals[t.currentInterval]
+}
+
+// TotalDuration returns the length of the whole workout, every interval of
+// every round, or 0 when it loops forever.
+func (t *Timer) TotalDuration() time.Duration {
+	if t.rounds == 0 {
+		return 0
+	}
+	return t.roundLength() * time.Duration(t.rounds)
+}
+
+// TotalRemaining returns what is left of the current interval, ignoring
+// overflow, plus every interval still to come. It is 0 when looping forever.
+func (t *Timer) TotalRemaining() time.Duration {
+	if t.rounds == 0 || t.state == TimerDone {
+		return 0
+	}
+	left := max(t.timeLeft, 0)
+	for _, d := range t.intervals[t.currentInterval+1:] {
+		left += d
+	}
+	return left + t.roundLength()*time.Duration(t.rounds-t.currentRound-1)
+}
+
+func (t *Timer) Upcoming(n int) []program.Interval {
+	if t.state == TimerDone {
+		return nil
+	}
+	var upcoming []program.Interval
+	interval, round := t.currentInterval, t.currentRound
+	for len(upcoming) < n {
+		interval++
+		if interval == len(t.intervals) {
+			interval, round = 0, round+1
+			if t.rounds > 0 && round == t.rounds {
+				break
+			}
+		}
+		next := program.Interval{Duration: t.intervals[interval]}
+		if t.labels != nil {
+			next.Label = t.labels[interval]
+		}
+		upcoming = append(upcoming, next)
+	}
+	return upcoming
+}
+
+func (t *Timer) roundLength() time.Duration {
+	var total time.Duration
+	for _, d := range t.intervals {
+		total += d
+	}
+	return total
+}
+
 func (t *Timer) isFinalInterval() bool {
 	return t.currentInterval == len(t.intervals)-1 && t.currentRound == t.rounds-1
 }
@@ -1,6 +1,7 @@
 package timer
 
 import (
+	"slices"
 	"testing"
 	"time"
 
@@ -86,4 +87,113 @@ func TestStateTransitions(t *testing.T) {
 	}
 }
 
+func TestCrossedSecond(t *testing.T) {
+	timer := newManual(5 * time.Second)
+	timer.Start()
+
+	steps := []struct {
+		elapsed time.Duration
+		want    int
+	}{
+		{500 * time.Millisecond, 0},  // 4.5s, still shows 5
+		{600 * time.Millisecond, 4},  // 3.9s
+		{300 * time.Millisecond, 0},  // 3.6s
+		{1500 * time.Millisecond, 3}, // 2.1s, a long tick crossing one second
+		{1200 * time.Millisecond, 1}, // 0.9s, skipped past 2
+		{time.Second, 0},             // reaching zero is not a second boundary
+		{time.Second, 0},             // overflow
+	}
+	for i, step := range steps {
+		timer.Tick(step.elapsed)
+		if got := timer.CrossedSecond(); got != step.want {
+			t.Errorf("step %d: expected %d, got %d", i, step.want, got)
+		}
+	}
+}
+
+func TestTimeDisplayAt(t *testing.T) {
+	timer := New([]time.Duration{10 * time.Second}, 0, types.ModeManual)
+	timer.Start()
+
+	steps := []struct {
+		elapsed       time.Duration
+		tenths, whole time.Duration
+	}{
+		{130 * time.Millisecond, 9900 * time.Millisecond, 10 * time.Second}, // 9.87s rounds up
+		{870 * time.Millisecond, 9 * time.Second, 9 * time.Second},          // exactly 9s
+		{9 * time.Second, 0, 0},
+		{1250 * time.Millisecond, 1200 * time.Millisecond, time.Second}, // overflow rounds down
+	}
+	for i, step := range steps {
+		timer.Tick(step.elapsed)
+		if got := timer.TimeDisplayAt(100 * time.Millisecond); got != step.tenths {
+			t.Errorf("step %d: expected %v, got %v", i, step.tenths, got)
+		}
+		if got := timer.TimeDisplay(); got != step.whole {
+			t.Errorf("step %d: expected whole %v, got %v", i, step.whole, got)
+		}
+	}
+}
+
+func TestTotalRemaining(t *testing.T) {
+	timer := New([]time.Duration{45 * time.Second, 15 * time.Second}, 3, types.ModeManual).
+		WithLabels([]string{"work", "rest"})
+	if got := timer.TotalDuration(); got != 3*time.Minute {
+		t.Fatalf("expected 3m total, got %v", got)
+	}
+	timer.Start()
+
+	steps := []struct {
+		do    func()
+		want  time.Duration
+		label string
+	}{
+		{func() {}, 3 * time.Minute, "work"},
+		{func() { timer.Tick(10 * time.Second) }, 170 * time.Second, "work"},
+		{func() { timer.Tick(40 * time.Second) }, 135 * time.Second, "work"}, // overflow doesn't count
+		{timer.Next, 135 * time.Second, "rest"},
+		{timer.Next, 2 * time.Minute, "work"},
+		{func() { timer.Next(); timer.Next(); timer.Next(); timer.Next() }, 0, "work"}, // done
+	}
+	for i, step := range steps {
+		step.do()
+		if got := timer.TotalRemaining(); got != step.want {
+			t.Errorf("step %d: expected %v remaining, got %v", i, step.want, got)
+		}
+		if got := timer.Label(); got != step.label {
+			t.Errorf("step %d: expected label %q, got %q", i, step.label, got)
+		}
+	}
+
+	forever := New([]time.Duration{time.Minute}, 0, types.ModeAuto)
+	if forever.TotalDuration() != 0 || forever.TotalRemaining() != 0 {
+		t.Errorf("expected no total when looping forever, got %v and %v", forever.TotalDuration(), forever.TotalRemaining())
+	}
+}
+
+func TestUpcoming(t *testing.T) {
+	work, rest := program.Interval{Label: "work", Duration: 45 * time.Second}, program.Interval{Label: "rest", Duration: 15 * time.Second}
+	timer := New([]time.Duration{45 * time.Second, 15 * time.Second}, 2, types.ModeAuto).
+		WithLabels([]string{"work", "rest"})
+	timer.Start()
+
+	if got := timer.Upcoming(5); !slices.Equal(got, []program.Interval{rest, work, rest}) {
+		t.Errorf("expected the rest of the workout, got %v", got)
+	}
+	if got := timer.Upcoming(2); !slices.Equal(got, []program.Interval{rest, work}) {
+		t.Errorf("expected two intervals, got %v", got)
+	}
+	timer.Next()
+	timer.Next()
+	timer.Next()
+	if got := timer.Upcoming(3); len(got) != 0 {
+		t.Errorf("expected nothing after the last interval, got %v", got)
+	}
+
+	forever := New([]time.Duration{time.Minute}, 0, types.ModeAuto)
+	if got := forever.Upcoming(3); len(got) != 3 || got[2].Duration != time.Minute {
+		t.Errorf("expected a looping program to repeat, got %v", got)
+	}
+}
+
 // Tests for Next() behavior are in next_test.go, added after implementation.
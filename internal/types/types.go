@@ -1,8 +1,30 @@
 package types
 
+import "fmt"
+
 type Mode int
 
 const (
 	ModeAuto Mode = iota
 	ModeManual
 )
+
+func (m Mode) String() string {
+	if m == ModeManual {
+		return "manual"
+	}
+	return "auto"
+}
+
+// UnmarshalText lets a Mode be read from config as "auto" or "manual".
+func (m *Mode) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "auto":
+		*m = ModeAuto
+	case "manual":
+		*m = ModeManual
+	default:
+		return fmt.Errorf("invalid mode %q: expected auto or manual", text)
+	}
+	return nil
+}